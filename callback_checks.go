@@ -0,0 +1,139 @@
+package gochecks
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// NewHTTPCallbackCheck returns a check function that request a given url sending a callback url (in the "callback"
+// query parameter) and wait until the remote service request the callback url. The callback url is served by the given
+// server, that is started with the first execution and handle callbackPath (other paths are served by the server
+// Handler, if any). The state is "critical" when the request fails or there is no callback before the timeout, and the
+// time until the callback is received is returned as metric
+func NewHTTPCallbackCheck(host, service string, server *http.Server, checkURL string, callbackPath string, timeout time.Duration) CheckFunction {
+	callbacks := newCallbackListener(server, callbackPath)
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		callbackURL, err := callbacks.start()
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		token := RandomCacheBuster()
+		received := callbacks.wait(token)
+		defer callbacks.cancel(token)
+
+		target, err := url.Parse(checkURL)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		query := target.Query()
+		query.Set("callback", callbackURL+"?token="+token)
+		target.RawQuery = query.Encode()
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), target.String())
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		response.Body.Close()
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		select {
+		case <-received:
+			result.State = StateOK
+		case <-time.After(timeout - time.Now().Sub(t1)):
+			result.Description = "No callback received"
+		}
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		return result
+	})
+}
+
+// callbackListener serve the callback path with the given server and notify the callbacks received for every token
+type callbackListener struct {
+	server *http.Server
+	path   string
+
+	mutex   sync.Mutex
+	url     string
+	pending map[string]chan struct{}
+}
+
+func newCallbackListener(server *http.Server, path string) *callbackListener {
+	return &callbackListener{server: server, path: path, pending: map[string]chan struct{}{}}
+}
+
+// start starts the server the first time is invoked, returning the callback url
+func (l *callbackListener) start() (string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.url != "" {
+		return l.url, nil
+	}
+
+	listener, err := net.Listen("tcp", l.server.Addr)
+	if err != nil {
+		return "", err
+	}
+	callbackHost, port, _ := net.SplitHostPort(listener.Addr().String())
+	if addrHost, _, err := net.SplitHostPort(l.server.Addr); err == nil && addrHost != "" {
+		callbackHost = addrHost
+	} else if hostname, err := os.Hostname(); err == nil {
+		callbackHost = hostname
+	}
+
+	next := l.server.Handler
+	if next == nil {
+		next = http.NotFoundHandler()
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", next)
+	mux.HandleFunc(l.path, l.handle)
+	l.server.Handler = mux
+	go l.server.Serve(listener)
+
+	l.url = fmt.Sprintf("http://%s%s", net.JoinHostPort(callbackHost, port), l.path)
+	return l.url, nil
+}
+
+func (l *callbackListener) handle(w http.ResponseWriter, r *http.Request) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	received, ok := l.pending[r.URL.Query().Get("token")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	close(received)
+	delete(l.pending, r.URL.Query().Get("token"))
+}
+
+// wait returns a channel that is closed when the callback for the token is received
+func (l *callbackListener) wait(token string) <-chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	received := make(chan struct{})
+	l.pending[token] = received
+	return received
+}
+
+func (l *callbackListener) cancel(token string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.pending, token)
+}
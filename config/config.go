@@ -0,0 +1,304 @@
+// Package config builds gochecks checks from a YAML or JSON description, so the checks can be defined declaratively.
+//
+// Example (YAML):
+//
+//	checks:
+//	  - type: http
+//	    host: golang
+//	    service: http
+//	    interval: 30s
+//	    parameters:
+//	      url: https://golang.org
+//	      timeout: 5s
+//	    thresholds:
+//	      warning_if_greater_than: 500
+//	    tags: [web]
+package config
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/aleasoluciones/gochecks"
+	"gopkg.in/yaml.v2"
+)
+
+// File the description of the checks
+type File struct {
+	Checks []Check `json:"checks" yaml:"checks"`
+}
+
+// Check the description of a check: the type of check (http, tcp, ping...) with its parameters, the thresholds to
+// change the state depending on the metric, the tags and the execution interval
+type Check struct {
+	Type       string                 `json:"type" yaml:"type"`
+	Host       string                 `json:"host" yaml:"host"`
+	Service    string                 `json:"service" yaml:"service"`
+	Parameters map[string]interface{} `json:"parameters" yaml:"parameters"`
+	Thresholds Thresholds             `json:"thresholds" yaml:"thresholds"`
+	Tags       []string               `json:"tags" yaml:"tags"`
+	Interval   string                 `json:"interval" yaml:"interval"`
+}
+
+// Thresholds the metric thresholds of a check, applied with the threshold modifiers of the check function
+type Thresholds struct {
+	CriticalIfLessThan    *float32 `json:"critical_if_less_than" yaml:"critical_if_less_than"`
+	CriticalIfGreaterThan *float32 `json:"critical_if_greater_than" yaml:"critical_if_greater_than"`
+	WarningIfLessThan     *float32 `json:"warning_if_less_than" yaml:"warning_if_less_than"`
+	WarningIfGreaterThan  *float32 `json:"warning_if_greater_than" yaml:"warning_if_greater_than"`
+}
+
+// ScheduledCheck a check function with the interval to execute it (see CheckEngine.AddCheck and Scheduler.Register)
+type ScheduledCheck struct {
+	Check    gochecks.CheckFunction
+	Interval time.Duration
+}
+
+// DefaultInterval interval of the checks without interval
+const DefaultInterval = 60 * time.Second
+
+// Builder function that build a check function of a type from the host, service and parameters of the description
+type Builder func(host, service string, parameters *Parameters) (gochecks.CheckFunction, error)
+
+// builders builders of every check type, guarded by the mutex as Register can be called while building checks
+var builders = struct {
+	sync.RWMutex
+	types map[string]Builder
+}{types: map[string]Builder{
+	"http": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewHTTPCheckerWithTimeout(host, service, p.String("url"), p.Duration("timeout", 10*time.Second)), p.Err()
+	},
+	"http_content": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewHTTPContentChecker(host, service, p.String("url"), p.String("content")), p.Err()
+	},
+	"tcp": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewTCPPortChecker(host, service, p.String("ip"), p.RequiredInt("port"), p.Duration("timeout", 10*time.Second)), p.Err()
+	},
+	"ping": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewPingChecker(host, service, p.String("ip")), p.Err()
+	},
+	"ping6": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewPing6Checker(host, service, p.String("ip")), p.Err()
+	},
+	"tls_certificate": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewTLSCertificateChecker(host, service, p.String("addr"), p.Int("warn_days", 30), p.Int("crit_days", 7)), p.Err()
+	},
+	"dns": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewDNSChecker(host, service, p.String("fqdn"), p.OptionalString("record_type", "A"),
+			p.Strings("expected"), p.OptionalString("resolver", "")), p.Err()
+	},
+	"ssh": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewSSHChecker(host, service, p.String("addr"), p.Duration("timeout", 10*time.Second)), p.Err()
+	},
+	"mysql": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewMysqlConnectionCheck(host, service, p.String("uri")), p.Err()
+	},
+	"postgres": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewPostgresConnectionCheck(host, service, p.String("uri")), p.Err()
+	},
+	"redis": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewRedisChecker(host, service, p.String("addr"), p.OptionalString("password", "")), p.Err()
+	},
+	"mongodb": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewMongoDBChecker(host, service, p.String("uri")), p.Err()
+	},
+	"rabbitmq_queue_len": func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		return gochecks.NewRabbitMQQueueLenCheck(host, service, p.String("uri"), p.String("queue"), p.Int("max", 0)), p.Err()
+	},
+}}
+
+// Register add a builder for a new check type (or replace the builder of an existing one)
+func Register(checkType string, builder Builder) {
+	builders.Lock()
+	defer builders.Unlock()
+	builders.types[checkType] = builder
+}
+
+// Load read a YAML (.yaml or .yml extension) or JSON file and build its checks
+func Load(path string) ([]ScheduledCheck, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return ParseJSON(data)
+	}
+}
+
+// ParseYAML build the checks of a YAML description
+func ParseYAML(data []byte) ([]ScheduledCheck, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return Build(file)
+}
+
+// ParseJSON build the checks of a JSON description
+func ParseJSON(data []byte) ([]ScheduledCheck, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return Build(file)
+}
+
+// Build build the checks of a description
+func Build(file File) ([]ScheduledCheck, error) {
+	checks := make([]ScheduledCheck, 0, len(file.Checks))
+	for i, c := range file.Checks {
+		check, err := c.build()
+		if err != nil {
+			return nil, fmt.Errorf("check %d (%s %s): %s", i, c.Host, c.Service, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+func (c Check) build() (ScheduledCheck, error) {
+	builders.RLock()
+	builder, ok := builders.types[c.Type]
+	builders.RUnlock()
+	if !ok {
+		return ScheduledCheck{}, fmt.Errorf("unknown check type %q", c.Type)
+	}
+	interval := DefaultInterval
+	if c.Interval != "" {
+		var err error
+		interval, err = time.ParseDuration(c.Interval)
+		if err != nil {
+			return ScheduledCheck{}, err
+		}
+		if interval <= 0 {
+			return ScheduledCheck{}, fmt.Errorf("invalid interval %s, it must be positive", c.Interval)
+		}
+	}
+
+	check, err := builder(c.Host, c.Service, &Parameters{values: c.Parameters})
+	if err != nil {
+		return ScheduledCheck{}, err
+	}
+	if c.Thresholds.CriticalIfLessThan != nil {
+		check = check.CriticalIfLessThan(*c.Thresholds.CriticalIfLessThan)
+	}
+	if c.Thresholds.CriticalIfGreaterThan != nil {
+		check = check.CriticalIfGreaterThan(*c.Thresholds.CriticalIfGreaterThan)
+	}
+	if c.Thresholds.WarningIfLessThan != nil {
+		check = check.WarningIfLessThan(*c.Thresholds.WarningIfLessThan)
+	}
+	if c.Thresholds.WarningIfGreaterThan != nil {
+		check = check.WarningIfGreaterThan(*c.Thresholds.WarningIfGreaterThan)
+	}
+	if len(c.Tags) > 0 {
+		check = check.Tags(c.Tags...)
+	}
+	return ScheduledCheck{Check: check, Interval: interval}, nil
+}
+
+// Parameters the parameters of a check description. The getters record the first missing or invalid parameter, that is
+// returned by Err
+type Parameters struct {
+	values map[string]interface{}
+	err    error
+}
+
+// Err returns the first missing or invalid parameter found by the getters
+func (p *Parameters) Err() error {
+	return p.err
+}
+
+func (p *Parameters) fail(format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf(format, args...)
+	}
+}
+
+// String returns a required string parameter
+func (p *Parameters) String(name string) string {
+	value, ok := p.values[name]
+	if !ok {
+		p.fail("missing parameter %s", name)
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// OptionalString returns a string parameter or the default value when it is not defined
+func (p *Parameters) OptionalString(name, defaultValue string) string {
+	if _, ok := p.values[name]; !ok {
+		return defaultValue
+	}
+	return p.String(name)
+}
+
+// Strings returns a list of strings parameter (empty when not defined)
+func (p *Parameters) Strings(name string) []string {
+	values, ok := p.values[name].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, fmt.Sprintf("%v", value))
+	}
+	return result
+}
+
+// Int returns an integer parameter or the default value when it is not defined
+func (p *Parameters) Int(name string, defaultValue int) int {
+	if _, ok := p.values[name]; !ok {
+		return defaultValue
+	}
+	return p.RequiredInt(name)
+}
+
+// RequiredInt returns a required integer parameter. The JSON numbers are accepted when they have no decimals
+func (p *Parameters) RequiredInt(name string) int {
+	value, ok := p.values[name]
+	if !ok {
+		p.fail("missing parameter %s", name)
+		return 0
+	}
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int(v)
+		}
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	p.fail("invalid integer parameter %s: %v", name, value)
+	return 0
+}
+
+// Duration returns a duration parameter (as "5s") or the default value when it is not defined
+func (p *Parameters) Duration(name string, defaultValue time.Duration) time.Duration {
+	value, ok := p.values[name]
+	if !ok {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(fmt.Sprintf("%v", value))
+	if err != nil {
+		p.fail("invalid duration parameter %s: %v", name, value)
+	}
+	return d
+}
@@ -0,0 +1,90 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleasoluciones/gochecks"
+	. "github.com/aleasoluciones/gochecks/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	Register("fixed", func(host, service string, p *Parameters) (gochecks.CheckFunction, error) {
+		metric := p.Int("metric", 0)
+		return func() gochecks.Event {
+			return gochecks.Event{Host: host, Service: service, State: gochecks.StateOK, Metric: float32(metric)}
+		}, p.Err()
+	})
+}
+
+func TestParseYAML(t *testing.T) {
+	t.Parallel()
+
+	checks, err := ParseYAML([]byte(`
+checks:
+  - type: fixed
+    host: host
+    service: service
+    interval: 30s
+    parameters:
+      metric: 600
+    thresholds:
+      warning_if_greater_than: 500
+    tags: [web]
+  - type: tcp
+    host: host
+    service: tcp
+    parameters:
+      ip: 127.0.0.1
+      port: 8080
+`))
+
+	assert.Nil(t, err)
+	assert.Len(t, checks, 2)
+	assert.Equal(t, 30*time.Second, checks[0].Interval)
+	assert.Equal(t, DefaultInterval, checks[1].Interval)
+	result := checks[0].Check()
+	assert.Equal(t, "host", result.Host)
+	assert.Equal(t, float32(600), result.Metric)
+	assert.Equal(t, gochecks.StateWarning, result.State)
+	assert.Equal(t, []string{"web"}, result.Tags)
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	checks, err := ParseJSON([]byte(`{"checks": [
+		{"type": "fixed", "host": "host", "service": "service", "interval": "1m",
+		 "parameters": {"metric": 1000000}, "thresholds": {"critical_if_less_than": 10}},
+		{"type": "tcp", "host": "host", "service": "tcp", "parameters": {"ip": "127.0.0.1", "port": "8080"}}
+	]}`))
+
+	assert.Nil(t, err)
+	assert.Len(t, checks, 2)
+	assert.Equal(t, time.Minute, checks[0].Interval)
+	result := checks[0].Check()
+	assert.Equal(t, float32(1000000), result.Metric)
+	assert.Equal(t, gochecks.StateOK, result.State)
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for description, data := range map[string]string{
+		"unknown check type":         `{"checks": [{"type": "unknown", "host": "host", "service": "service"}]}`,
+		"missing parameter url":      `{"checks": [{"type": "http", "host": "host", "service": "service"}]}`,
+		"missing parameter port":     `{"checks": [{"type": "tcp", "host": "host", "service": "service", "parameters": {"ip": "127.0.0.1"}}]}`,
+		"invalid integer parameter":  `{"checks": [{"type": "tcp", "host": "host", "service": "service", "parameters": {"ip": "127.0.0.1", "port": 80.5}}]}`,
+		"invalid duration parameter": `{"checks": [{"type": "ssh", "host": "host", "service": "service", "parameters": {"addr": "127.0.0.1:22", "timeout": "5"}}]}`,
+		"invalid duration":           `{"checks": [{"type": "fixed", "host": "host", "service": "service", "interval": "often"}]}`,
+		"it must be positive":        `{"checks": [{"type": "fixed", "host": "host", "service": "service", "interval": "0s"}]}`,
+	} {
+		checks, err := ParseJSON([]byte(data))
+		assert.Nil(t, checks, description)
+		if assert.NotNil(t, err, description) {
+			assert.Contains(t, err.Error(), description)
+		}
+	}
+}
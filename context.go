@@ -0,0 +1,68 @@
+package gochecks
+
+import (
+	"context"
+)
+
+// CheckFunctionCtx type for a function that return a event and honor the cancellation and deadline of the given context.
+// The checkers of network services have a Ctx variant (NewTCPPortCheckerCtx, NewMysqlConnectionCheckCtx...) returning a
+// CheckFunctionCtx, the checks of the HTTP checkers honor the context when executed with NewCheckFunctionCtx
+type CheckFunctionCtx func(ctx context.Context) Event
+
+// NewCheckFunctionCtx returns a CheckFunctionCtx that execute the given check function. The http requests of the checks
+// returned by the HTTP checkers (and their HTTP check modifiers) are done with the context, so they are cancelled with
+// it. Any other check is executed in its own goroutine and, when the context is done before it finishes, a critical
+// result with the given host and service is returned without waiting for it. Use the Ctx variants of the checkers to
+// abort the checks instead of leaving them running
+func NewCheckFunctionCtx(host, service string, f CheckFunction) CheckFunctionCtx {
+	if c := f.httpCheck(); c != nil {
+		settings, check := c.settings, c.check
+		return func(ctx context.Context) Event {
+			callSettings := settings
+			callSettings.ctx = ctx
+			return callSettings.run(check)
+		}
+	}
+
+	return func(ctx context.Context) Event {
+		result, ok := runWithContext(ctx, f)
+		if !ok {
+			return Event{Host: host, Service: service, State: StateCritical, Description: ctx.Err().Error()}
+		}
+		return result
+	}
+}
+
+// runWithContext execute the check function in its own goroutine and returns its result, or false when the context is
+// done before it finishes
+func runWithContext(ctx context.Context, f CheckFunction) (Event, bool) {
+	if ctx.Err() != nil {
+		return Event{}, false
+	}
+	results := make(chan Event, 1)
+	go func() {
+		results <- f()
+	}()
+	select {
+	case result := <-results:
+		return result, true
+	case <-ctx.Done():
+		return Event{}, false
+	}
+}
+
+// CheckFunction returns a check function that execute the CheckFunctionCtx with a background context
+func (f CheckFunctionCtx) CheckFunction() CheckFunction {
+	return func() Event {
+		return f(context.Background())
+	}
+}
+
+// ctxError returns the error of the context when it is done, as the errors of the operations aborted by the
+// cancellation (closed connections...) are less descriptive, and the given error otherwise
+func ctxError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package gochecks
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// NewDiskUsageChecker returns a check function that return the percentage of used disk space of the filesystem mounted
+// at mountpoint as metric (as df, the space reserved to root is not considered available), to be used with the
+// threshold modifiers (ex: WarningIfGreaterThan(80).CriticalIfGreaterThan(90))
+func NewDiskUsageChecker(host, service, mountpoint string) CheckFunction {
+	return func() Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var stat syscall.Statfs_t
+		err := syscall.Statfs(mountpoint, &stat)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		used := uint64(stat.Blocks) - uint64(stat.Bfree)
+		total := used + uint64(stat.Bavail)
+		if total == 0 {
+			result.Description = fmt.Sprintf("No blocks in %s", mountpoint)
+			return result
+		}
+		result.Metric = float32(float64(used) * 100 / float64(total))
+		result.Description = fmt.Sprintf("%.1f%% used in %s", result.Metric, mountpoint)
+		result.State = StateOK
+		return result
+	}
+}
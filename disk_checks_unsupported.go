@@ -0,0 +1,12 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package gochecks
+
+// NewDiskUsageChecker returns a check function that return the percentage of used disk space of the filesystem mounted
+// at mountpoint as metric. It is not supported in this platform, so the state is always unknown
+func NewDiskUsageChecker(host, service, mountpoint string) CheckFunction {
+	return func() Event {
+		return Event{Host: host, Service: service, State: StateUnknown, Description: "Disk usage not supported"}
+	}
+}
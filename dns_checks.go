@@ -0,0 +1,124 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dnsTimeout = 5 * time.Second
+)
+
+// NewDNSChecker returns a check function that resolve the records of the given type (A, AAAA, CNAME, MX, NS, TXT or PTR)
+// of a fqdn using the given resolver (ip or ip:port, the system resolver when empty) and validate that the records are
+// the expected ones (when expected is not empty). The lookup time is returned as metric and the state is "critical"
+// when the resolution fails, returns no records or the records are not the expected ones
+func NewDNSChecker(host, service, fqdn, recordType string, expected []string, resolver string) CheckFunction {
+	return NewDNSCheckerCtx(host, service, fqdn, recordType, expected, resolver).CheckFunction()
+}
+
+// NewDNSCheckerCtx returns a CheckFunctionCtx like NewDNSChecker that honor the context
+func NewDNSCheckerCtx(host, service, fqdn, recordType string, expected []string, resolver string) CheckFunctionCtx {
+	r := net.DefaultResolver
+	if resolver != "" {
+		if _, _, err := net.SplitHostPort(resolver); err != nil {
+			resolver = net.JoinHostPort(resolver, "53")
+		}
+		r = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, resolver)
+			},
+		}
+	}
+	expectedRecords := normalizeDNSRecords(recordType, expected)
+
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		ctx, cancel := context.WithTimeout(ctx, dnsTimeout)
+		defer cancel()
+		var t1 = time.Now()
+		records, err := lookupDNSRecords(ctx, r, fqdn, recordType)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		records = normalizeDNSRecords(recordType, records)
+		if len(records) == 0 {
+			result.Description = fmt.Sprintf("No %s records", recordType)
+			return result
+		}
+		result.Description = strings.Join(records, ",")
+		if len(expectedRecords) > 0 && strings.Join(records, ",") != strings.Join(expectedRecords, ",") {
+			result.Description = fmt.Sprintf("Records %s, expected %s", strings.Join(records, ","), strings.Join(expectedRecords, ","))
+			return result
+		}
+		result.State = StateOK
+		return result
+	}
+}
+
+func lookupDNSRecords(ctx context.Context, r *net.Resolver, fqdn, recordType string) ([]string, error) {
+	records := []string{}
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA":
+		addrs, err := r.LookupIPAddr(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if (addr.IP.To4() != nil) == (strings.ToUpper(recordType) == "A") {
+				records = append(records, addr.IP.String())
+			}
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, cname)
+	case "MX":
+		mxs, err := r.LookupMX(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			records = append(records, mx.Host)
+		}
+	case "NS":
+		nss, err := r.LookupNS(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			records = append(records, ns.Host)
+		}
+	case "TXT":
+		return r.LookupTXT(ctx, fqdn)
+	case "PTR":
+		return r.LookupAddr(ctx, fqdn)
+	default:
+		return nil, fmt.Errorf("Unsupported record type %s", recordType)
+	}
+	return records, nil
+}
+
+// normalizeDNSRecords returns the records sorted, without the trailing dot and in lower case (except TXT records)
+func normalizeDNSRecords(recordType string, records []string) []string {
+	normalized := make([]string, 0, len(records))
+	for _, record := range records {
+		if strings.ToUpper(recordType) != "TXT" {
+			record = strings.ToLower(strings.TrimSuffix(record, "."))
+		}
+		normalized = append(normalized, record)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,85 @@
+package gochecks
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// NewDoHCheck returns a check function that resolve the A records of a domain using a DNS-over-HTTPS (RFC 8484)
+// resolver and return the round trip time as metric. The state is "critical" when the request fails or the response has
+// no A records
+func NewDoHCheck(host, service, dohURL, domainToResolve string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		name, err := dnsmessage.NewName(strings.TrimSuffix(domainToResolve, ".") + ".")
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		query := dnsmessage.Message{
+			Header:    dnsmessage.Header{RecursionDesired: true},
+			Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+		}
+		packed, err := query.Pack()
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+
+		request, err := s.newRequest("POST", dohURL, bytes.NewReader(packed))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Content-Type", "application/dns-message")
+		request.Header.Set("Accept", "application/dns-message")
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = "Error geting body"
+			return result
+		}
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		var answer dnsmessage.Message
+		if err := answer.Unpack(body); err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		if answer.RCode != dnsmessage.RCodeSuccess {
+			result.Description = answer.RCode.String()
+			return result
+		}
+		addresses := []string{}
+		for _, resource := range answer.Answers {
+			if a, ok := resource.Body.(*dnsmessage.AResource); ok {
+				addresses = append(addresses, fmt.Sprintf("%d.%d.%d.%d", a.A[0], a.A[1], a.A[2], a.A[3]))
+			}
+		}
+		if len(addresses) == 0 {
+			result.Description = fmt.Sprintf("No A records for %s", domainToResolve)
+			return result
+		}
+		result.State = StateOK
+		result.Description = strings.Join(addresses, ",")
+		return result
+	})
+}
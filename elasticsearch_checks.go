@@ -0,0 +1,105 @@
+package gochecks
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"encoding/json"
+)
+
+// NewElasticsearchQueryCheck returns a check function that count the documents of the given index pattern matching a
+// query (request body of the _count api, for example {"query": {"match": {"level": "error"}}}) and return the count as
+// metric
+func NewElasticsearchQueryCheck(host, service, esURL, indexPattern string, query json.RawMessage, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		countURL := strings.TrimRight(esURL, "/") + "/" + indexPattern + "/_count"
+		request, err := s.newRequest("POST", countURL, bytes.NewReader(query))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Content-Type", "application/json")
+
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		var count struct {
+			Count int64 `json:"count"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&count)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		result.State = StateOK
+		result.Metric = float32(count.Count)
+		return result
+	})
+}
+
+// elasticsearchTimeout timeout of the Elasticsearch health requests
+const elasticsearchTimeout = 10 * time.Second
+
+// NewElasticsearchHealthChecker returns a check function that get the cluster health (_cluster/health api) of an
+// Elasticsearch cluster. The green status is ok, yellow warning and red critical. The response time is returned as
+// metric and the number of nodes, unassigned shards and pending tasks as attributes
+func NewElasticsearchHealthChecker(host, service, esURL string) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(elasticsearchTimeout), strings.TrimRight(esURL, "/")+"/_cluster/health")
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		var health struct {
+			ClusterName          string `json:"cluster_name"`
+			Status               string `json:"status"`
+			NumberOfNodes        int    `json:"number_of_nodes"`
+			UnassignedShards     int    `json:"unassigned_shards"`
+			NumberOfPendingTasks int    `json:"number_of_pending_tasks"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&health)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		result.Attributes = map[string]string{
+			"number_of_nodes":         fmt.Sprintf("%d", health.NumberOfNodes),
+			"unassigned_shards":       fmt.Sprintf("%d", health.UnassignedShards),
+			"number_of_pending_tasks": fmt.Sprintf("%d", health.NumberOfPendingTasks),
+		}
+		result.Description = fmt.Sprintf("Cluster %s %s", health.ClusterName, health.Status)
+		switch health.Status {
+		case "green":
+			result.State = StateOK
+		case "yellow":
+			result.State = StateWarning
+		case "red":
+			result.State = StateCritical
+		default:
+			result.State = StateUnknown
+		}
+		return result
+	})
+}
@@ -0,0 +1,63 @@
+package gochecks
+
+// EventBuilder helper to build events step by step
+type EventBuilder struct {
+	event Event
+}
+
+// New return a EventBuilder for a event with the given host and service
+func New(host, service string) *EventBuilder {
+	return &EventBuilder{event: Event{Host: host, Service: service}}
+}
+
+// State set the state of the event
+func (b *EventBuilder) State(s string) *EventBuilder {
+	b.event.State = s
+	return b
+}
+
+// Metric set the metric of the event
+func (b *EventBuilder) Metric(v float32) *EventBuilder {
+	b.event.Metric = v
+	return b
+}
+
+// Description set the description of the event
+func (b *EventBuilder) Description(d string) *EventBuilder {
+	b.event.Description = d
+	return b
+}
+
+// Tags add the given tags to the event
+func (b *EventBuilder) Tags(t ...string) *EventBuilder {
+	b.event.Tags = append(b.event.Tags, t...)
+	return b
+}
+
+// Attribute add a attribute to the event
+func (b *EventBuilder) Attribute(key, value string) *EventBuilder {
+	if b.event.Attributes == nil {
+		b.event.Attributes = map[string]string{}
+	}
+	b.event.Attributes[key] = value
+	return b
+}
+
+// TTL set the TTL (in seconds) of the event
+func (b *EventBuilder) TTL(ttl float32) *EventBuilder {
+	b.event.TTL = ttl
+	return b
+}
+
+// Build return the built event
+func (b *EventBuilder) Build() Event {
+	event := b.event
+	event.Tags = append([]string(nil), b.event.Tags...)
+	if b.event.Attributes != nil {
+		event.Attributes = map[string]string{}
+		for key, value := range b.event.Attributes {
+			event.Attributes[key] = value
+		}
+	}
+	return event
+}
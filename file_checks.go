@@ -0,0 +1,32 @@
+package gochecks
+
+import (
+	"io/ioutil"
+	"path/filepath"
+)
+
+// NewDirectoryFileCountCheck returns a check function that count the files of a directory (not recursively) with a name
+// matching the given pattern (filepath.Match syntax) and return the count as metric. The state is critical when the
+// directory can't be read
+func NewDirectoryFileCountCheck(host, service, dir string, pattern string) CheckFunction {
+	return func() Event {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		count := 0
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			matched, err := filepath.Match(pattern, file.Name())
+			if err != nil {
+				return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+			}
+			if matched {
+				count++
+			}
+		}
+		return Event{Host: host, Service: service, State: StateOK, Metric: float32(count)}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/aleasoluciones/gochecks
+
+go 1.24
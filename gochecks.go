@@ -4,11 +4,21 @@
 package gochecks
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/aleasoluciones/goaleasoluciones/scheduledtask"
 )
 
+// States of the events
+const (
+	StateOK       = "ok"
+	StateWarning  = "warning"
+	StateCritical = "critical"
+	StateUnknown  = "unknown"
+)
+
 // Event is the check result, very inspired and compatible with Riemann events
 type Event struct {
 	Host        string
@@ -19,6 +29,35 @@ type Event struct {
 	Tags        []string
 	Attributes  map[string]string
 	TTL         float32
+	// Annotations audit metadata of the check (created_by, runbook_url, owner_team...), see WithAnnotation
+	Annotations map[string]string `json:",omitempty"`
+}
+
+// IsZero returns true when the event is a zero (empty) Event, as the ones returned by the checks that have nothing to
+// report (see Deduplicate). The CheckEngine does not publish zero events
+func IsZero(e Event) bool {
+	return e.Host == "" && e.Service == "" && e.State == "" && e.Metric == nil && e.Description == "" &&
+		len(e.Tags) == 0 && len(e.Attributes) == 0 && e.TTL == 0 && len(e.Annotations) == 0
+}
+
+// Validate returns an error when the event has no host or service, the state is not one of the State constants or the
+// metric is not numeric
+func (e Event) Validate() error {
+	if e.Host == "" {
+		return errors.New("Event without host")
+	}
+	if e.Service == "" {
+		return errors.New("Event without service")
+	}
+	switch e.State {
+	case StateOK, StateWarning, StateCritical, StateUnknown:
+	default:
+		return fmt.Errorf("Invalid state %q", e.State)
+	}
+	if _, ok := metricValue(e.Metric); e.Metric != nil && !ok {
+		return fmt.Errorf("Invalid metric %v", e.Metric)
+	}
+	return nil
 }
 
 type EventFilterFunction func(event Event) (bool, Event)
@@ -65,7 +104,10 @@ func (ce *CheckEngine) AddResult(event Event) {
 // AddCheck schedule a new check to be executed with the given period
 func (ce *CheckEngine) AddCheck(check CheckFunction, period time.Duration) {
 	scheduledtask.NewScheduledTask(func() {
-		ce.results <- check()
+		result := check()
+		if !IsZero(result) {
+			ce.results <- result
+		}
 	}, period, 0)
 }
 
@@ -74,7 +116,9 @@ func (ce *CheckEngine) AddCheck(check CheckFunction, period time.Duration) {
 func (ce *CheckEngine) AddMultiCheck(check MultiCheckFunction, period time.Duration) {
 	scheduledtask.NewScheduledTask(func() {
 		for _, result := range check() {
-			ce.results <- result
+			if !IsZero(result) {
+				ce.results <- result
+			}
 		}
 	}, period, 0)
 }
@@ -0,0 +1,62 @@
+package gochecks
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"encoding/json"
+)
+
+// NewGraphQLCheck returns a check function that send a GraphQL query (for example the introspection query
+// {__schema{queryType{name}}}) and validate that the response has no errors and the field at expectedFieldPath of the
+// data (for example "__schema.queryType.name") is not null. The metric is the response time
+func NewGraphQLCheck(host, service, url string, query string, expectedFieldPath string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		body, err := json.Marshal(map[string]string{"query": query})
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request, err := s.newRequest("POST", url, bytes.NewReader(body))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Content-Type", "application/json")
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		var graphQLResponse struct {
+			Data   interface{} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&graphQLResponse)
+		if err != nil {
+			result.Description = fmt.Sprintf("Response %d: %s", response.StatusCode, err.Error())
+			return result
+		}
+		if len(graphQLResponse.Errors) > 0 {
+			result.Description = graphQLResponse.Errors[0].Message
+			return result
+		}
+		value, found := jsonPathValue(graphQLResponse.Data, expectedFieldPath)
+		if !found || value == nil {
+			result.Description = fmt.Sprintf("Null field %s", expectedFieldPath)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
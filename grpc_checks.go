@@ -0,0 +1,95 @@
+package gochecks
+
+import (
+	"context"
+	"time"
+
+	"crypto/tls"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewGRPCUnaryCheck returns a check function that invoke a unary RPC method (fullMethod, as /package.Service/Method) of
+// a gRPC server with the given request. The latency is returned as metric and any error as critical
+func NewGRPCUnaryCheck(host, service, target, fullMethod string, request, response proto.Message, timeout time.Duration) CheckFunction {
+	return NewGRPCUnaryCheckCtx(host, service, target, fullMethod, request, response, timeout).CheckFunction()
+}
+
+// NewGRPCUnaryCheckCtx returns a CheckFunctionCtx like NewGRPCUnaryCheck that honor the context
+func NewGRPCUnaryCheckCtx(host, service, target, fullMethod string, request, response proto.Message, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		var t1 = time.Now()
+		err = conn.Invoke(ctx, fullMethod, request, response)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = status.Convert(err).Message()
+			return result
+		}
+		result.State = StateOK
+		return result
+	}
+}
+
+// grpcHealthTimeout timeout of the gRPC health check requests
+const grpcHealthTimeout = 10 * time.Second
+
+// NewGRPCHealthChecker returns a check function that query the standard gRPC health service (grpc.health.v1.Health)
+// of a server for the given service name (empty for the overall server health), using TLS or a plaintext connection.
+// SERVING is ok, NOT_SERVING is critical and any other status is unknown. The latency is returned as metric
+func NewGRPCHealthChecker(host, service, target, serviceName string, useTLS bool) CheckFunction {
+	return NewGRPCHealthCheckerCtx(host, service, target, serviceName, useTLS).CheckFunction()
+}
+
+// NewGRPCHealthCheckerCtx returns a CheckFunctionCtx like NewGRPCHealthChecker that honor the context
+func NewGRPCHealthCheckerCtx(host, service, target, serviceName string, useTLS bool) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		transportCredentials := insecure.NewCredentials()
+		if useTLS {
+			transportCredentials = credentials.NewTLS(&tls.Config{})
+		}
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(transportCredentials))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(ctx, grpcHealthTimeout)
+		defer cancel()
+		var t1 = time.Now()
+		response, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: serviceName})
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = status.Convert(err).Message()
+			return result
+		}
+		result.Description = response.GetStatus().String()
+		switch response.GetStatus() {
+		case grpc_health_v1.HealthCheckResponse_SERVING:
+			result.State = StateOK
+		case grpc_health_v1.HealthCheckResponse_NOT_SERVING:
+			result.State = StateCritical
+		default:
+			result.State = StateUnknown
+		}
+		return result
+	}
+}
@@ -0,0 +1,95 @@
+package gochecks
+
+import (
+	"regexp"
+	"time"
+
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// NewHTTPPageAssetCheck returns a multi check function that get a given html page and check every asset (<link href>,
+// <script src> and <img src>) whose url matches some of the given regular expressions. An event is generated for every
+// asset, with the asset url appended to the service and the response time as metric. When the page can't be loaded a
+// single critical event is generated
+func NewHTTPPageAssetCheck(host, service, pageURL string, assetPatterns []string, timeout time.Duration) MultiCheckFunction {
+	return newHTTPMultiCheck(defaultHTTPSettings(), func(s httpSettings) []Event {
+		pageResult := Event{Host: host, Service: service, State: StateCritical}
+
+		client := s.client(timeout)
+		response, err := s.get(client, pageURL)
+		if err != nil {
+			pageResult.Description = err.Error()
+			return []Event{pageResult}
+		}
+		defer response.Body.Close()
+		if state, description, _ := successValidator(response); state != StateOK {
+			pageResult.Description = description
+			return []Event{pageResult}
+		}
+		assets := pageAssets(response.Request.URL, html.NewTokenizer(response.Body), assetPatterns)
+
+		results := []Event{}
+		for _, asset := range assets {
+			result := Event{Host: host, Service: service + " " + asset, State: StateCritical}
+			var t1 = time.Now()
+			assetResponse, err := s.get(client, asset)
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if err != nil {
+				result.Description = err.Error()
+			} else {
+				assetResponse.Body.Close()
+				result.State, result.Description, _ = successValidator(assetResponse)
+			}
+			results = append(results, result)
+		}
+		return results
+	})
+}
+
+// pageAssets returns the absolute urls of the assets of the html document that match some of the patterns, without
+// duplicates
+func pageAssets(base *url.URL, tokenizer *html.Tokenizer, patterns []string) []string {
+	assets := []string{}
+	seen := map[string]bool{}
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return assets
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		attribute := ""
+		switch token.Data {
+		case "link":
+			attribute = "href"
+		case "script", "img":
+			attribute = "src"
+		default:
+			continue
+		}
+		for _, attr := range token.Attr {
+			if attr.Key != attribute || attr.Val == "" {
+				continue
+			}
+			reference, err := url.Parse(attr.Val)
+			if err != nil {
+				continue
+			}
+			asset := base.ResolveReference(reference).String()
+			if seen[asset] {
+				continue
+			}
+			for _, pattern := range patterns {
+				if matched, _ := regexp.MatchString(pattern, asset); matched {
+					assets = append(assets, asset)
+					seen[asset] = true
+					break
+				}
+			}
+		}
+	}
+}
@@ -1,11 +1,28 @@
 package gochecks
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"mime"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
 )
 
 // ValidateHTTPResponseFunction function type that should validate a http response and return the state (ok, critical, warning) and error description for a check. (Used with NewGenericHTTPChecker)
@@ -15,19 +32,19 @@ type ValidateHTTPResponseFunction func(resp *http.Response) (state, description
 func BodyGreaterThan(minLength int) ValidateHTTPResponseFunction {
 	return func(httpResp *http.Response) (state, description string) {
 		if httpResp.StatusCode != 200 {
-			return "critical", fmt.Sprintf("Response %d", httpResp.StatusCode)
+			return StateCritical, fmt.Sprintf("Response %d", httpResp.StatusCode)
 		}
 		if httpResp.Body == nil {
-			return "critical", fmt.Sprintf("Empty body")
+			return StateCritical, fmt.Sprintf("Empty body")
 		}
 		body, err := ioutil.ReadAll(httpResp.Body)
 		if err != nil {
-			return "critical", fmt.Sprintf("Error geting body")
+			return StateCritical, fmt.Sprintf("Error geting body")
 		}
 		if len(body) < minLength {
-			return "critical", fmt.Sprintf("Obtained %d bytes, expected more than %d", len(body), minLength)
+			return StateCritical, fmt.Sprintf("Obtained %d bytes, expected more than %d", len(body), minLength)
 		}
-		return "ok", ""
+		return StateOK, ""
 	}
 }
 
@@ -38,46 +55,1636 @@ type ValidateContentFunction func(content string) (state, description string)
 func BodyValidation(bodyValidationFunc ValidateContentFunction) ValidateHTTPResponseFunction {
 	return func(httpResp *http.Response) (state, description string) {
 		if httpResp.StatusCode != 200 {
-			return "critical", fmt.Sprintf("Response %d", httpResp.StatusCode)
+			return StateCritical, fmt.Sprintf("Response %d", httpResp.StatusCode)
 		}
 		if httpResp.Body == nil {
-			return "critical", fmt.Sprintf("Empty body")
+			return StateCritical, fmt.Sprintf("Empty body")
 		}
 		body, err := ioutil.ReadAll(httpResp.Body)
 		if err != nil {
-			return "critical", fmt.Sprintf("Error geting body")
+			return StateCritical, fmt.Sprintf("Error geting body")
 		}
 		return bodyValidationFunc(string(body))
 	}
 }
 
+// ResponseValidator function type that should validate a http response and return the state (ok, critical, warning),
+// error description and metric for a check. (Used with NewHTTPCheckWithValidator)
+type ResponseValidator func(*http.Response) (state, description string, metric float32)
+
+type requestStartKey struct{}
+
+// NewHTTPCheckWithValidator returns a check function that get a given url and use the given validator to obtain the
+// state, description and metric of the result from the http response
+func NewHTTPCheckWithValidator(host, service, url string, timeout time.Duration, validator ResponseValidator) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("GET", url, nil)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		var t1 = time.Now()
+		request = request.WithContext(context.WithValue(request.Context(), requestStartKey{}, t1))
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		result.State, result.Description, result.Metric = validator(response)
+		return result
+	})
+}
+
+// ResponseTime returns the milliseconds elapsed since the request of a response validated by a ResponseValidator was sent
+func ResponseTime(response *http.Response) float32 {
+	start, ok := response.Request.Context().Value(requestStartKey{}).(time.Time)
+	if !ok {
+		return 0
+	}
+	return float32((time.Now().Sub(start)).Nanoseconds() / 1e6)
+}
+
+// successValidator validate that the response status code is a success (2xx), with the response time as metric
+func successValidator(httpResp *http.Response) (string, string, float32) {
+	if httpResp.StatusCode >= 200 && httpResp.StatusCode < 300 {
+		return StateOK, "", ResponseTime(httpResp)
+	}
+	return StateCritical, fmt.Sprintf("Response %d", httpResp.StatusCode), ResponseTime(httpResp)
+}
+
 // NewGenericHTTPChecker returns a check function that can check the returned http response of a http get with a given validation function
 func NewGenericHTTPChecker(host, service, url string, validationFunc ValidateHTTPResponseFunction) CheckFunction {
-	return func() Event {
+	return NewHTTPCheckWithValidator(host, service, url, 0,
+		func(httpResp *http.Response) (string, string, float32) {
+			milliseconds := ResponseTime(httpResp)
+			state, description := validationFunc(httpResp)
+			return state, description, milliseconds
+		})
+}
+
+// NewHTTPChecker returns a check function that get a given url and validate if the return code is the expected one
+func NewHTTPChecker(host, service, url string, expectedStatusCode int) CheckFunction {
+	return NewHTTPCheckWithValidator(host, service, url, 0,
+		func(httpResp *http.Response) (string, string, float32) {
+			if httpResp.StatusCode == expectedStatusCode {
+				return StateOK, "", ResponseTime(httpResp)
+			}
+			return StateCritical, fmt.Sprintf("Response %d", httpResp.StatusCode), ResponseTime(httpResp)
+		})
+}
+
+// contentRegexPrefix prefix of the NewHTTPContentChecker texts that are regular expressions
+const contentRegexPrefix = "regex:"
+
+// NewHTTPContentChecker returns a check function that get a given url and validate that the body contains the given
+// text or, when it starts with "regex:", matches the regular expression that follows. The state is "critical" when the
+// status code is not 200, the body does not contain or match the text or the regular expression is not valid
+func NewHTTPContentChecker(host, service, url, containsOrRegex string) CheckFunction {
+	if !strings.HasPrefix(containsOrRegex, contentRegexPrefix) {
+		return NewGenericHTTPChecker(host, service, url, BodyValidation(func(content string) (string, string) {
+			if strings.Contains(content, containsOrRegex) {
+				return StateOK, ""
+			}
+			return StateCritical, fmt.Sprintf("Body does not contain %s", containsOrRegex)
+		}))
+	}
+
+	pattern := strings.TrimPrefix(containsOrRegex, contentRegexPrefix)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return func() Event {
+			return Event{Host: host, Service: service, State: StateCritical, Description: fmt.Sprintf("Invalid regular expression %s: %s", pattern, err)}
+		}
+	}
+	return NewGenericHTTPChecker(host, service, url, BodyValidation(func(content string) (string, string) {
+		if re.MatchString(content) {
+			return StateOK, ""
+		}
+		return StateCritical, fmt.Sprintf("Body does not match %s", pattern)
+	}))
+}
+
+// NewHTTPCheckerWithTimeout returns a check function that get a given url with a timeout and validate that the return
+// code is a success (2xx), with the response time as metric. (NewHTTPChecker validates a given status code without
+// timeout)
+func NewHTTPCheckerWithTimeout(host, service, url string, timeout time.Duration) CheckFunction {
+	return NewHTTPCheckWithValidator(host, service, url, timeout, successValidator)
+}
+
+// NewHTTPCORSCheck returns a check function that send a CORS preflight request (OPTIONS) with the given origin and validate
+// that the returned Access-Control-Allow-Origin header is the expected one
+func NewHTTPCORSCheck(host, service, url, origin string, expectedAllowOrigin string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("OPTIONS", url, nil)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Origin", origin)
+		request.Header.Set("Access-Control-Request-Method", "GET")
+
 		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		allowOrigin := response.Header.Get("Access-Control-Allow-Origin")
+		if allowOrigin == "" {
+			result.Description = "No Access-Control-Allow-Origin header"
+			return result
+		}
+		if allowOrigin != expectedAllowOrigin {
+			result.Description = fmt.Sprintf("Access-Control-Allow-Origin %s, expected %s", allowOrigin, expectedAllowOrigin)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
 
-		response, err := http.Get(url)
+// NewHTTPLocationCheck returns a check function that get a given url without following redirects and validate that the
+// return code and the Location header are the expected ones
+func NewHTTPLocationCheck(host, service, url, expectedLocation string, expectedStatus int, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.noRedirectClient(timeout), url)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != expectedStatus {
+			result.Description = fmt.Sprintf("Response %d, expected %d", response.StatusCode, expectedStatus)
+			return result
+		}
+		location := response.Header.Get("Location")
+		if location != expectedLocation {
+			result.Description = fmt.Sprintf("Location %s, expected %s", location, expectedLocation)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPChangeDetectionCheck returns a check function that get a given url and keep the SHA-256 fingerprints of the last
+// different bodies (as many as the given tolerance). The state is "warning" when the body is different from all the
+// recent ones
+func NewHTTPChangeDetectionCheck(host, service, url string, tolerance float64, timeout time.Duration) CheckFunction {
+	maxFingerprints := int(tolerance)
+	if maxFingerprints < 1 {
+		maxFingerprints = 1
+	}
+	var mutex sync.Mutex
+	var fingerprints [][sha256.Size]byte
+
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = fmt.Sprintf("Error geting body")
+			return result
+		}
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		fingerprint := sha256.Sum256(body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, known := range fingerprints {
+			if known == fingerprint {
+				result.State = StateOK
+				return result
+			}
+		}
+		result.State = StateWarning
+		result.Description = fmt.Sprintf("Body changed, fingerprint %x", fingerprint)
+		if len(fingerprints) == 0 {
+			result.State = StateOK
+			result.Description = ""
+		}
+		fingerprints = append(fingerprints, fingerprint)
+		if len(fingerprints) > maxFingerprints {
+			fingerprints = fingerprints[1:]
+		}
+		return result
+	})
+}
+
+// NewHTTPTotalCountCheck returns a check function that get a given url and return as metric the total count of items
+// obtained from the given header (when headerName is not empty) or from the json body field at the given jsonPath
+// (for example "meta.total"). The state is critical when the count can't be obtained
+func NewHTTPTotalCountCheck(host, service, url, headerName string, jsonPath string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		response, err := s.get(s.client(timeout), url)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		if headerName != "" && response.Header.Get(headerName) != "" {
+			count, err := strconv.ParseFloat(response.Header.Get(headerName), 32)
+			if err != nil {
+				result.Description = fmt.Sprintf("Invalid %s header: %s", headerName, err.Error())
+				return result
+			}
+			result.State = StateOK
+			result.Metric = float32(count)
+			return result
+		}
+		if jsonPath == "" {
+			result.Description = fmt.Sprintf("No %s header", headerName)
+			return result
+		}
+
+		var document interface{}
+		err = json.NewDecoder(response.Body).Decode(&document)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		value, found := jsonPathValue(document, jsonPath)
+		if !found {
+			result.Description = fmt.Sprintf("No %s field", jsonPath)
+			return result
+		}
+		count, ok := value.(float64)
+		if !ok {
+			result.Description = fmt.Sprintf("Field %s is not a number", jsonPath)
+			return result
+		}
+		result.State = StateOK
+		result.Metric = float32(count)
+		return result
+	})
+}
+
+// NewHTTPTTFBCheck returns a check function that get a given url and return as metric the time to first byte (from the
+// request is sent until the first byte of the response is received) in milliseconds. The total time is included in the
+// description
+func NewHTTPTTFBCheck(host, service, url string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("GET", url, nil)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		var wroteRequest, firstByte time.Time
+		trace := &httptrace.ClientTrace{
+			WroteRequest: func(info httptrace.WroteRequestInfo) {
+				wroteRequest = time.Now()
+			},
+			GotFirstResponseByte: func() {
+				firstByte = time.Now()
+			},
+		}
+		request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		_, err = io.Copy(ioutil.Discard, response.Body)
 		milliseconds := float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
-		result := Event{Host: host, Service: service, State: "critical", Metric: milliseconds}
+		if err != nil {
+			result.Description = fmt.Sprintf("Error geting body")
+			return result
+		}
+
+		result.Metric = float32((firstByte.Sub(wroteRequest)).Nanoseconds() / 1e6)
+		result.Description = fmt.Sprintf("Total time %.0f ms", milliseconds)
+		if response.StatusCode != 200 {
+			result.Description += fmt.Sprintf(", response %d", response.StatusCode)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPCheckNoRedirect returns a check function that get a given url without following redirects. The state is "ok"
+// when the first response status code is a success or a redirection (2xx or 3xx)
+func NewHTTPCheckNoRedirect(host, service, url string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		var t1 = time.Now()
+		response, err := s.get(s.noRedirectClient(timeout), url)
+		milliseconds := float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		result := Event{Host: host, Service: service, State: StateCritical, Metric: milliseconds}
 		if err != nil {
 			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+		if location := response.Header.Get("Location"); location != "" {
+			result.Description = fmt.Sprintf("Response %d, Location %s", response.StatusCode, location)
+		}
+		if response.StatusCode >= 200 && response.StatusCode < 400 {
+			result.State = StateOK
+		}
+		return result
+	})
+}
+
+// NewHTTPCheckWithProxy returns a check function that get a given url through a proxy and validate that the response is
+// a success (2xx). The proxy can be a http CONNECT proxy (http://host:port) or a SOCKS5 proxy (socks5://host:port)
+func NewHTTPCheckWithProxy(host, service, url, proxyURL string, timeout time.Duration) CheckFunction {
+	proxy, err := parseProxyURL(proxyURL)
+	if err != nil {
+		return func() Event {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+	}
+	return NewHTTPCheckWithValidator(host, service, url, timeout, successValidator).withHTTPSettings(func(s *httpSettings) {
+		s.proxy = proxy
+	})
+}
+
+// NewHTTPCheckWithSNI returns a check function that get a given url connecting to the given server ip, but using the
+// given sniHostname as TLS server name and Host header, and validate that the response is a success (2xx). Useful to
+// check a backend before the DNS points to it
+func NewHTTPCheckWithSNI(host, service, url, sniHostname, serverIP string, timeout time.Duration) CheckFunction {
+	return NewHTTPCheckWithValidator(host, service, url, timeout, successValidator).withHTTPSettings(func(s *httpSettings) {
+		s.serverIP = serverIP
+		s.serverName = sniHostname
+	})
+}
+
+// NewHTTPWithDNSTimingCheck returns a multi check function that get a given url using a new connection and return two
+// events, one (service followed by " dns") with the DNS resolution time and another one (service followed by " http")
+// with the rest of the http round trip time (excluding the DNS resolution). Both in milliseconds
+func NewHTTPWithDNSTimingCheck(host, service, url string, timeout time.Duration) MultiCheckFunction {
+	settings := defaultHTTPSettings()
+	settings.disableKeepAlives = true
+	return newHTTPMultiCheck(settings, func(s httpSettings) []Event {
+		dnsResult := Event{Host: host, Service: service + " dns", State: StateCritical}
+		httpResult := Event{Host: host, Service: service + " http", State: StateCritical}
+
+		request, err := s.newRequest("GET", url, nil)
+		if err != nil {
+			dnsResult.Description = err.Error()
+			httpResult.Description = err.Error()
+			return []Event{dnsResult, httpResult}
+		}
+		var dnsStart, dnsDone time.Time
+		var dnsErr error
+		trace := &httptrace.ClientTrace{
+			DNSStart: func(info httptrace.DNSStartInfo) {
+				dnsStart = time.Now()
+			},
+			DNSDone: func(info httptrace.DNSDoneInfo) {
+				dnsDone = time.Now()
+				dnsErr = info.Err
+			},
+		}
+		request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		total := time.Now().Sub(t1)
+		dns := dnsDone.Sub(dnsStart)
+
+		dnsResult.Metric = float32(dns.Nanoseconds() / 1e6)
+		if dnsErr != nil {
+			dnsResult.Description = dnsErr.Error()
 		} else {
-			if response.Body != nil {
+			dnsResult.State = StateOK
+		}
+		httpResult.Metric = float32((total - dns).Nanoseconds() / 1e6)
+		if err != nil {
+			httpResult.Description = err.Error()
+			return []Event{dnsResult, httpResult}
+		}
+		defer response.Body.Close()
+		httpResult.State, httpResult.Description, _ = successValidator(response)
+		return []Event{dnsResult, httpResult}
+	})
+}
+
+// NewOAuth2TokenCheck returns a check function that request a token to a OAuth2 token endpoint using the client
+// credentials grant and validate that the response contains a access_token. The token is discarded, only the response
+// time (metric) and status are recorded
+func NewOAuth2TokenCheck(host, service, tokenURL, clientID, clientSecret string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("POST", tokenURL, strings.NewReader("grant_type=client_credentials"))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("Accept", "application/json")
+		request.SetBasicAuth(clientID, clientSecret)
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		var token struct {
+			AccessToken string `json:"access_token"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&token)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		if token.AccessToken == "" {
+			result.Description = "No access_token in the response"
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPCheckWithRetryAfter returns a check function that get a given url and validate that the response is a success
+// (2xx). When the response is a 429 (Too Many Requests) the request is retried up to maxRetries times, waiting the time
+// indicated by the Retry-After header (limited by the overall timeout, 0 means no timeout) and at least
+// minRetryAfterWait. A 429 after all the retries is a "warning"
+func NewHTTPCheckWithRetryAfter(host, service, url string, maxRetries int, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		deadline := t1.Add(timeout)
+		for retry := 0; ; retry++ {
+			var remaining time.Duration
+			if timeout > 0 {
+				remaining = deadline.Sub(time.Now())
+				if remaining <= 0 {
+					result.State = StateCritical
+					result.Description = fmt.Sprintf("Timeout after %d retries", retry)
+					return result
+				}
+			}
+			response, err := s.get(s.client(remaining), url)
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if err != nil {
+				result.Description = err.Error()
+				return result
+			}
+			response.Body.Close()
+			if response.StatusCode != http.StatusTooManyRequests {
+				result.State, result.Description, _ = successValidator(response)
+				return result
+			}
+
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("Response %d after %d retries", response.StatusCode, retry)
+			wait := retryAfter(response.Header.Get("Retry-After"))
+			if wait < minRetryAfterWait {
+				wait = minRetryAfterWait
+			}
+			if retry >= maxRetries || (timeout > 0 && time.Now().Add(wait).After(deadline)) {
+				return result
+			}
+			time.Sleep(wait)
+		}
+	})
+}
+
+// minRetryAfterWait min wait before retrying a 429 response, used when the Retry-After header is missing, invalid or
+// asks to retry immediately, so the server is not hammered
+const minRetryAfterWait = time.Second
+
+// retryAfter returns the wait duration of a Retry-After header value (seconds or http date)
+func retryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(time.Now())
+	}
+	return 0
+}
+
+// NewHTTPCSPCheck returns a check function that get a given url and validate that the Content-Security-Policy header
+// contains all the required directives, with a value containing the expected one. The state is "critical" when there is
+// no header and "warning" when some directive is missing or incomplete
+func NewHTTPCSPCheck(host, service, url string, requiredDirectives map[string]string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		policy := response.Header.Get("Content-Security-Policy")
+		if policy == "" {
+			result.Description = "No Content-Security-Policy header"
+			return result
+		}
+		directives := map[string]string{}
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) > 0 {
+				directives[strings.ToLower(fields[0])] = strings.Join(fields[1:], " ")
+			}
+		}
+
+		invalid := []string{}
+		for name, expected := range requiredDirectives {
+			value, ok := directives[strings.ToLower(name)]
+			if !ok || !strings.Contains(value, expected) {
+				invalid = append(invalid, name)
+			}
+		}
+		if len(invalid) > 0 {
+			sort.Strings(invalid)
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("Missing or incomplete directives: %s", strings.Join(invalid, ","))
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPStreamingCheck returns a check function that get a given streaming url and read at least minChunks chunks of
+// the response body, each of them received before chunkTimeout. The total latency is returned as metric and the state
+// is "critical" when less chunks arrive in time
+func NewHTTPStreamingCheck(host, service, url string, minChunks int, chunkTimeout time.Duration, totalTimeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(totalTimeout), url)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		chunks := make(chan error)
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			send := func(err error) bool {
+				select {
+				case chunks <- err:
+					return true
+				case <-done:
+					return false
+				}
+			}
+			buffer := make([]byte, 4096)
+			for {
+				n, err := response.Body.Read(buffer)
+				if n > 0 && !send(nil) {
+					return
+				}
+				if err != nil {
+					send(err)
+					return
+				}
+			}
+		}()
+
+		received := 0
+		for received < minChunks {
+			select {
+			case err := <-chunks:
+				if err != nil {
+					result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+					result.Description = fmt.Sprintf("Received %d chunks of %d: %s", received, minChunks, err.Error())
+					return result
+				}
+				received++
+			case <-time.After(chunkTimeout):
+				result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+				result.Description = fmt.Sprintf("Received %d chunks of %d, chunk timeout", received, minChunks)
+				return result
+			}
+		}
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPCheckWithConnectionResetRetry returns a check function that get a given url and validate that the response is a
+// success (2xx). The request is retried up to maxRetries times only when it fails before receiving the response because
+// the connection is reset (connection reset by peer or unexpected EOF) or times out, any other error (including a
+// connection closed without response) or http response is not retried
+func NewHTTPCheckWithConnectionResetRetry(host, service, url string, maxRetries int, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		for retry := 0; ; retry++ {
+			response, err := s.get(s.client(timeout), url)
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if err == nil {
 				defer response.Body.Close()
+				result.State, result.Description, _ = successValidator(response)
+				return result
+			}
+			result.Description = err.Error()
+			if retry >= maxRetries || !isRetryableConnectionError(err) {
+				return result
 			}
-			result.State, result.Description = validationFunc(response)
 		}
+	})
+}
+
+// isRetryableConnectionError returns true if the error is caused by a connection reset, an unexpected EOF or a timeout
+func isRetryableConnectionError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// NewHTTPSTimingCheck returns a multi check function that get a given url using a new connection and return three events
+// with the TLS handshake time (service followed by " tls"), the TCP connect time (service followed by " connect") and
+// the total time to first byte (service followed by " ttfb"). All in milliseconds
+func NewHTTPSTimingCheck(host, service, url string, timeout time.Duration) MultiCheckFunction {
+	settings := defaultHTTPSettings()
+	settings.disableKeepAlives = true
+	return newHTTPMultiCheck(settings, func(s httpSettings) []Event {
+		tlsResult := Event{Host: host, Service: service + " tls", State: StateCritical}
+		connectResult := Event{Host: host, Service: service + " connect", State: StateCritical}
+		ttfbResult := Event{Host: host, Service: service + " ttfb", State: StateCritical}
+		// the trace callbacks can run after Do returns (ex: a dial finishing after the timeout), so the results and the
+		// times they record are guarded by the mutex
+		var mutex sync.Mutex
+		results := func(description string) []Event {
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, result := range []*Event{&tlsResult, &connectResult, &ttfbResult} {
+				if result.State != StateOK {
+					result.Description = description
+				}
+			}
+			return []Event{tlsResult, connectResult, ttfbResult}
+		}
+
+		request, err := s.newRequest("GET", url, nil)
+		if err != nil {
+			return results(err.Error())
+		}
+		var t1, connectStart, tlsStart time.Time
+		trace := &httptrace.ClientTrace{
+			ConnectStart: func(network, addr string) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				connectStart = time.Now()
+			},
+			ConnectDone: func(network, addr string, err error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err == nil {
+					connectResult.State = StateOK
+					connectResult.Metric = float32((time.Now().Sub(connectStart)).Nanoseconds() / 1e6)
+				}
+			},
+			TLSHandshakeStart: func() {
+				mutex.Lock()
+				defer mutex.Unlock()
+				tlsStart = time.Now()
+			},
+			TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err == nil {
+					tlsResult.State = StateOK
+					tlsResult.Metric = float32((time.Now().Sub(tlsStart)).Nanoseconds() / 1e6)
+				}
+			},
+			GotFirstResponseByte: func() {
+				mutex.Lock()
+				defer mutex.Unlock()
+				ttfbResult.State = StateOK
+				ttfbResult.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			},
+		}
+		request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+
+		t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			return results(err.Error())
+		}
+		defer response.Body.Close()
+		mutex.Lock()
+		handshake := !tlsStart.IsZero()
+		mutex.Unlock()
+		if !handshake {
+			return results("No TLS handshake")
+		}
+		return results("")
+	})
+}
+
+// NewHTTPRedirectChainCheck returns a check function that get a given url following the redirects one by one, and
+// validate that the redirect locations are the expected ones and the last response is a 200. The timeout (0 means no
+// timeout) limits the whole chain
+func NewHTTPRedirectChainCheck(host, service, startURL string, expectedChain []string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		deadline := t1.Add(timeout)
+		chain := []string{}
+		current := startURL
+		for {
+			var remaining time.Duration
+			if timeout > 0 {
+				remaining = deadline.Sub(time.Now())
+				if remaining <= 0 {
+					result.Description = fmt.Sprintf("Timeout following redirect chain at %s", current)
+					return result
+				}
+			}
+			response, err := s.get(s.noRedirectClient(remaining), current)
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if err != nil {
+				result.Description = err.Error()
+				return result
+			}
+			response.Body.Close()
+
+			if response.StatusCode < 300 || response.StatusCode >= 400 {
+				if response.StatusCode != 200 {
+					result.Description = fmt.Sprintf("Response %d from %s", response.StatusCode, current)
+					return result
+				}
+				break
+			}
+			location, err := response.Location()
+			if err != nil {
+				result.Description = fmt.Sprintf("Response %d from %s without a valid Location", response.StatusCode, current)
+				return result
+			}
+			current = location.String()
+			chain = append(chain, current)
+			if len(chain) > len(expectedChain) {
+				break
+			}
+		}
+
+		if len(chain) != len(expectedChain) {
+			result.Description = fmt.Sprintf("Redirect chain %s, expected %s", strings.Join(chain, " -> "), strings.Join(expectedChain, " -> "))
+			return result
+		}
+		for i := range chain {
+			if chain[i] != expectedChain[i] {
+				result.Description = fmt.Sprintf("Redirect chain %s, expected %s", strings.Join(chain, " -> "), strings.Join(expectedChain, " -> "))
+				return result
+			}
+		}
+		result.State = StateOK
 		return result
+	})
+}
+
+// NewHTTPCacheHeaderCheck returns a check function that get a given url and validate the cache headers of the response:
+// ETag and Last-Modified (that can't be in the future) are present and Cache-Control max-age is at least maxAge seconds
+// (when maxAge > 0). The state is "critical" when a required header is missing or invalid and "warning" when an optional
+// one is missing
+func NewHTTPCacheHeaderCheck(host, service, url string, requireETag bool, requireLastModified bool, maxAge int, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		critical := []string{}
+		warning := []string{}
+		missing := func(required bool, problem string) {
+			if required {
+				critical = append(critical, problem)
+			} else {
+				warning = append(warning, problem)
+			}
+		}
+
+		if response.Header.Get("ETag") == "" {
+			missing(requireETag, "no ETag")
+		}
+		if value := response.Header.Get("Last-Modified"); value == "" {
+			missing(requireLastModified, "no Last-Modified")
+		} else if lastModified, err := http.ParseTime(value); err != nil || lastModified.After(time.Now()) {
+			missing(requireLastModified, fmt.Sprintf("invalid Last-Modified %s", value))
+		}
+		if maxAge > 0 {
+			age := -1
+			for _, directive := range strings.Split(response.Header.Get("Cache-Control"), ",") {
+				directive = strings.TrimSpace(strings.ToLower(directive))
+				if strings.HasPrefix(directive, "max-age=") {
+					age, err = strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+					if err != nil {
+						age = -1
+					}
+				}
+			}
+			if age < 0 {
+				critical = append(critical, "no Cache-Control max-age")
+			} else if age < maxAge {
+				critical = append(critical, fmt.Sprintf("Cache-Control max-age %d lower than %d", age, maxAge))
+			}
+		}
+
+		switch {
+		case len(critical) > 0:
+			result.Description = strings.Join(append(critical, warning...), ", ")
+		case len(warning) > 0:
+			result.State = StateWarning
+			result.Description = strings.Join(warning, ", ")
+		default:
+			result.State = StateOK
+		}
+		return result
+	})
+}
+
+// NewHTTPJitterCheck returns a check function that get a given url n times sequentially and return as metric the
+// standard deviation of the response times (in ms). The state is "critical" when it is greater than maxStdDevMs and
+// "warning" when it is greater than the 75% of maxStdDevMs
+func NewHTTPJitterCheck(host, service, url string, n int, maxStdDevMs float32, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		client := s.client(timeout)
+		times := make([]float64, 0, n)
+		for i := 0; i < n; i++ {
+			var t1 = time.Now()
+			response, err := s.get(client, url)
+			if err != nil {
+				result.Description = err.Error()
+				return result
+			}
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+			times = append(times, float64(time.Now().Sub(t1).Nanoseconds())/1e6)
+			if response.StatusCode < 200 || response.StatusCode >= 300 {
+				result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+				return result
+			}
+		}
+		if len(times) == 0 {
+			result.Description = "No requests performed"
+			return result
+		}
+
+		var mean, variance float64
+		for _, t := range times {
+			mean += t
+		}
+		mean /= float64(len(times))
+		for _, t := range times {
+			variance += (t - mean) * (t - mean)
+		}
+		stdDev := float32(math.Sqrt(variance / float64(len(times))))
+		result.Metric = stdDev
+		result.Description = fmt.Sprintf("Mean %.2fms stddev %.2fms", mean, stdDev)
+
+		switch {
+		case stdDev > maxStdDevMs:
+		case stdDev > 0.75*maxStdDevMs:
+			result.State = StateWarning
+		default:
+			result.State = StateOK
+		}
+		return result
+	})
+}
+
+// NewSSECheck returns a check function that connect to a given Server-Sent Events url and read the stream until an event
+// of the expected type is received (the events without type are "message" events). The time until the event is received
+// is returned as metric. The state is "critical" when the connection fails or no event is received before the timeout
+func NewSSECheck(host, service, url string, expectedEventType string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("GET", url, nil)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Accept", "text/event-stream")
+		request.Header.Set("Cache-Control", "no-cache")
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		eventType := ""
+		hasData := false
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				// an event is dispatched on a blank line when it has some data
+				if hasData {
+					if eventType == "" {
+						eventType = "message"
+					}
+					if eventType == expectedEventType {
+						result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+						result.State = StateOK
+						return result
+					}
+				}
+				eventType = ""
+				hasData = false
+				continue
+			}
+			field, value := line, ""
+			if i := strings.Index(line, ":"); i >= 0 {
+				field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+			}
+			switch field {
+			case "event":
+				eventType = value
+			case "data":
+				hasData = true
+			}
+		}
+
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err := scanner.Err(); err != nil {
+			result.Description = err.Error()
+		} else {
+			result.Description = fmt.Sprintf("Stream closed without %s event", expectedEventType)
+		}
+		return result
+	})
+}
+
+// NewHTTPJSONValidityCheck returns a check function that get a given url and validate that the response body is a valid
+// JSON document. The state is "critical" when the request fails or the body is not valid JSON
+func NewHTTPJSONValidityCheck(host, service, url string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = "Error geting body"
+			return result
+		}
+
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			result.Description = fmt.Sprintf("Response %d, invalid JSON (%s): %s", response.StatusCode,
+				response.Header.Get("Content-Type"), err)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPHeaderMetricCheck returns a check function that get a given url and return as metric the numeric value of the
+// given response header (as X-Queue-Depth). The state is "critical" when the header is missing or is not a number. Use
+// the threshold modifiers (CriticalIfGreaterThan...) to validate the value
+func NewHTTPHeaderMetricCheck(host, service, url, headerName string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		response, err := s.get(s.client(timeout), url)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		value := strings.TrimSpace(response.Header.Get(headerName))
+		if value == "" {
+			result.Description = fmt.Sprintf("Response %d without %s header", response.StatusCode, headerName)
+			return result
+		}
+		metric, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			result.Description = fmt.Sprintf("Invalid %s header value %s", headerName, value)
+			return result
+		}
+		result.Metric = float32(metric)
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPMultiRegionCheck returns a multi check function that get a given url through every SOCKS5 proxy of the given
+// map (region name to proxy address, as host:port or socks5://host:port). An event is generated for every region, with
+// the region name appended to the service and the latency as metric. The regions are checked in parallel
+func NewHTTPMultiRegionCheck(host, service, url string, timeout time.Duration, proxies map[string]string) MultiCheckFunction {
+	regions := make([]string, 0, len(proxies))
+	for region := range proxies {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	checks := make([]CheckFunction, len(regions))
+	for i, region := range regions {
+		proxyURL := proxies[region]
+		if !strings.Contains(proxyURL, "://") {
+			proxyURL = "socks5://" + proxyURL
+		}
+		checks[i] = NewHTTPCheckWithProxy(host, service+" "+region, url, proxyURL, timeout)
+	}
+
+	return func() []Event {
+		results := make([]Event, len(checks))
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func(i int, check CheckFunction) {
+				defer wg.Done()
+				results[i] = check()
+			}(i, check)
+		}
+		wg.Wait()
+		return results
 	}
 }
 
-// NewHTTPChecker returns a check function that get a given url and validate if the return code is the expected one
-func NewHTTPChecker(host, service, url string, expectedStatusCode int) CheckFunction {
-	return NewGenericHTTPChecker(host, service, url,
-		func(httpResp *http.Response) (string, string) {
-			if httpResp.StatusCode == expectedStatusCode {
-				return "ok", ""
+// NewHTTPPaginatedCheck returns a check function that get the pages 1 to pages of a paginated API and validate that every
+// page returns a 200. The page number replaces the {pageParam} placeholder of urlTemplate or, when there is no
+// placeholder, is sent in the pageParam query parameter. The total latency of all the pages is returned as metric
+func NewHTTPPaginatedCheck(host, service, urlTemplate string, pages int, pageParam string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		client := s.client(timeout)
+		placeholder := "{" + pageParam + "}"
+		var t1 = time.Now()
+		for page := 1; page <= pages; page++ {
+			pageURL := strings.Replace(urlTemplate, placeholder, strconv.Itoa(page), -1)
+			request, err := s.newRequest("GET", pageURL, nil)
+			if err != nil {
+				result.Description = err.Error()
+				return result
 			}
-			return "critical", fmt.Sprintf("Response %d", httpResp.StatusCode)
-		})
+			if pageURL == urlTemplate {
+				query := request.URL.Query()
+				query.Set(pageParam, strconv.Itoa(page))
+				request.URL.RawQuery = query.Encode()
+			}
+
+			response, err := client.Do(request)
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if err != nil {
+				result.Description = fmt.Sprintf("Page %d: %s", page, err)
+				return result
+			}
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if response.StatusCode != 200 {
+				result.Description = fmt.Sprintf("Page %d: Response %d", page, response.StatusCode)
+				return result
+			}
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// RetryConfig configure the retries of NewHTTPCheckWithSmartRetry. Only the responses with a retriable status code (as
+// 429 or 503) and, if RetryNetworkErrors, the network errors are retried, waiting InitialBackoff before the first retry
+// and doubling the wait (up to MaxBackoff, when not 0) before every next one
+type RetryConfig struct {
+	MaxRetries           int
+	RetriableStatusCodes []int
+	RetryNetworkErrors   bool
+	InitialBackoff       time.Duration
+	MaxBackoff           time.Duration
+}
+
+func (c RetryConfig) retriable(statusCode int) bool {
+	for _, code := range c.RetriableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHTTPCheckWithSmartRetry returns a check function that get a given url, retrying with exponential backoff only the
+// transient failures defined by the retry config. The permanent failures (as 404 or 500 when they are not configured as
+// retriable) are reported without retrying. The metric is the response time of the last request
+func NewHTTPCheckWithSmartRetry(host, service, url string, timeout time.Duration, retryConfig RetryConfig) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		client := s.client(timeout)
+		backoff := retryConfig.InitialBackoff
+		for retry := 0; ; retry++ {
+			var t1 = time.Now()
+			response, err := s.get(client, url)
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			transient := false
+			if err != nil {
+				result.Description = err.Error()
+				transient = retryConfig.RetryNetworkErrors
+			} else {
+				response.Body.Close()
+				result.State, result.Description, _ = successValidator(response)
+				if result.State == StateOK {
+					return result
+				}
+				transient = retryConfig.retriable(response.StatusCode)
+			}
+
+			if !transient {
+				return result
+			}
+			if retry >= retryConfig.MaxRetries {
+				result.Description = fmt.Sprintf("%s after %d retries", result.Description, retry)
+				return result
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+			if retryConfig.MaxBackoff != 0 && backoff > retryConfig.MaxBackoff {
+				backoff = retryConfig.MaxBackoff
+			}
+		}
+	})
+}
+
+// tlsVersionNames names of the TLS versions used in the descriptions
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+func tlsVersionName(version uint16) string {
+	if name, ok := tlsVersionNames[version]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// NewHTTPCheckWithMinTLSVersion returns a check function that get a given https url accepting only TLS versions greater
+// or equal than minVersion (tls.VersionTLS12...). The state is "critical" when the server can't negotiate a valid version,
+// with the version the server negotiates without the restriction in the description
+func NewHTTPCheckWithMinTLSVersion(host, service, url string, minVersion uint16, timeout time.Duration) CheckFunction {
+	settings := defaultHTTPSettings()
+	settings.minTLSVersion = minVersion
+	return newRegisteredHTTPCheck(settings, func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			if request, requestErr := s.newRequest("GET", url, nil); requestErr == nil && request.URL.Scheme == "https" {
+				if version, versionErr := negotiatedTLSVersion(request.Context(), request.URL.Host, timeout); versionErr == nil && version < minVersion {
+					result.Description = fmt.Sprintf("Server negotiates %s, minimum %s", tlsVersionName(version), tlsVersionName(minVersion))
+				}
+			}
+			return result
+		}
+		defer response.Body.Close()
+
+		if response.TLS == nil {
+			result.Description = "Not a TLS connection"
+			return result
+		}
+		result.State, result.Description, _ = successValidator(response)
+		if result.State == StateOK {
+			result.Description = tlsVersionName(response.TLS.Version)
+		}
+		return result
+	})
+}
+
+// negotiatedTLSVersion returns the TLS version negotiated with a server accepting any version
+func negotiatedTLSVersion(ctx context.Context, address string, timeout time.Duration) (uint16, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "443")
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config:    &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.(*tls.Conn).ConnectionState().Version, nil
+}
+
+// NewHTTPContentNegotiationCheck returns a check function that get a given url sending the given Accept header and
+// validate that the media type of the response Content-Type is the expected one. The state is "critical" when the
+// request fails or the content type is not the expected one
+func NewHTTPContentNegotiationCheck(host, service, url, acceptHeader, expectedContentType string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("GET", url, nil)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Accept", acceptHeader)
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if state, description, _ := successValidator(response); state != StateOK {
+			result.Description = description
+			return result
+		}
+
+		contentType := response.Header.Get("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !strings.EqualFold(mediaType, expectedContentType) {
+			result.Description = fmt.Sprintf("Content-Type %s, expected %s", contentType, expectedContentType)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// urlWithPayload returns the url with the payload appended to the value of every query parameter, or in a "q" parameter
+// when the url has no query parameters
+func urlWithPayload(rawURL, payload string) (string, error) {
+	request, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	query := request.URL.Query()
+	if len(query) == 0 {
+		query.Set("q", payload)
+	} else {
+		for _, values := range query {
+			for i := range values {
+				values[i] += payload
+			}
+		}
+	}
+	request.URL.RawQuery = query.Encode()
+	return request.URL.String(), nil
+}
+
+// NewSQLInjectionResponseCheck returns a check function that get a given url with the sqlPayload appended to its query
+// parameters (or in a "q" parameter when it has none) and validate that the response body does not contain any of the
+// forbidden patterns (as "syntax error" or "mysql_fetch", case insensitive) that reveal a SQL injection vulnerability
+func NewSQLInjectionResponseCheck(host, service, url string, sqlPayload string, forbiddenPatterns []string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		injectionURL, err := urlWithPayload(url, sqlPayload)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), injectionURL)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = "Error geting body"
+			return result
+		}
+
+		content := strings.ToLower(string(body))
+		for _, pattern := range forbiddenPatterns {
+			if strings.Contains(content, strings.ToLower(pattern)) {
+				result.Description = fmt.Sprintf("Response %d contains %s", response.StatusCode, pattern)
+				return result
+			}
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPRateLimitCheck returns a check function that get a given url requestsPerBurst times concurrently and validate
+// that the rate limit is enforced, that is, some of the responses has the expected status (typically 429). The state is
+// "critical" when no response has the expected status
+func NewHTTPRateLimitCheck(host, service, url string, requestsPerBurst int, expectedStatus int, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		client := s.client(timeout)
+		statusCodes := make([]int, requestsPerBurst)
+		errs := make([]error, requestsPerBurst)
+		var wg sync.WaitGroup
+		var t1 = time.Now()
+		for i := 0; i < requestsPerBurst; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				response, err := s.get(client, url)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				io.Copy(ioutil.Discard, response.Body)
+				response.Body.Close()
+				statusCodes[i] = response.StatusCode
+			}(i)
+		}
+		wg.Wait()
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+
+		counts := map[int]int{}
+		failed := 0
+		for i := range statusCodes {
+			if errs[i] != nil {
+				failed++
+				result.Description = errs[i].Error()
+				continue
+			}
+			counts[statusCodes[i]]++
+		}
+		if counts[expectedStatus] > 0 {
+			result.State = StateOK
+			result.Description = fmt.Sprintf("%d of %d requests limited", counts[expectedStatus], requestsPerBurst)
+			return result
+		}
+		if failed == requestsPerBurst {
+			return result
+		}
+		result.Description = fmt.Sprintf("No response %d in %d requests", expectedStatus, requestsPerBurst)
+		return result
+	})
+}
+
+// NewHTTPWAFCheck returns a check function that get a given url with a harmless attack payload (as
+// <script>alert(1)</script>) appended to its query parameters (or in a "q" parameter when it has none) and validate that
+// the WAF blocks the request with the blockedStatus (typically 403). The state is "critical" when the request is not
+// blocked (2xx response) and "warning" for other statuses
+func NewHTTPWAFCheck(host, service, url string, attackPayload string, blockedStatus int, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		attackURL, err := urlWithPayload(url, attackPayload)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), attackURL)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		response.Body.Close()
+
+		switch {
+		case response.StatusCode == blockedStatus:
+			result.State = StateOK
+		case response.StatusCode >= 200 && response.StatusCode < 300:
+			result.Description = fmt.Sprintf("Response %d, attack not blocked", response.StatusCode)
+		default:
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("Response %d, expected %d", response.StatusCode, blockedStatus)
+		}
+		return result
+	})
+}
+
+// NewHTTPJSONErrorCheck returns a check function that get a given url and validate that the field of the JSON response
+// at errorFieldPath (keys and array indexes separated by dots, as "error" or "errors.0.message") is absent, null, false
+// or empty. The state is "critical" when the response is not valid JSON or it has an error value
+func NewHTTPJSONErrorCheck(host, service, url, errorFieldPath string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = "Error geting body"
+			return result
+		}
+
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			result.Description = fmt.Sprintf("Response %d, invalid JSON: %s", response.StatusCode, err)
+			return result
+		}
+		value, _ := jsonPathValue(document, errorFieldPath)
+		switch v := value.(type) {
+		case nil:
+		case bool:
+			if v {
+				result.Description = fmt.Sprintf("%s is true", errorFieldPath)
+				return result
+			}
+		case string:
+			if v != "" {
+				result.Description = v
+				return result
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				result.Description = fmt.Sprintf("%v", v)
+				return result
+			}
+		case map[string]interface{}:
+			if len(v) > 0 {
+				result.Description = fmt.Sprintf("%v", v)
+				return result
+			}
+		default:
+			result.Description = fmt.Sprintf("%v", v)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// NewHTTPDeprecationCheck returns a check function that get a given url and validate that the response has no
+// deprecation header (as Deprecation or Sunset). The state is "warning" when the header is present, with its value (the
+// deprecation date) in the description
+func NewHTTPDeprecationCheck(host, service, url string, deprecationHeaderName string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		response.Body.Close()
+
+		if values, ok := response.Header[http.CanonicalHeaderKey(deprecationHeaderName)]; ok {
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("%s: %s", deprecationHeaderName, strings.Join(values, ", "))
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
+
+// sameSiteNames names of the SameSite modes used in the descriptions
+var sameSiteNames = map[http.SameSite]string{
+	0:                        "none set",
+	http.SameSiteDefaultMode: "default",
+	http.SameSiteLaxMode:     "Lax",
+	http.SameSiteStrictMode:  "Strict",
+	http.SameSiteNoneMode:    "None",
+}
+
+// NewHTTPCookieSecurityCheck returns a check function that get a given url and validate the security attributes of the
+// named cookie, set by the response or by some of the redirects followed. The state is "critical" when the cookie is not
+// set or some of the required attributes (Secure, HttpOnly and SameSite when sameSite is not 0) is missing
+func NewHTTPCookieSecurityCheck(host, service, url, cookieName string, requireSecure, requireHTTPOnly bool, sameSite http.SameSite, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		response.Body.Close()
+
+		var cookie *http.Cookie
+		for r := response; r != nil && cookie == nil; r = r.Request.Response {
+			for _, c := range r.Cookies() {
+				if c.Name == cookieName {
+					cookie = c
+				}
+			}
+		}
+		if cookie == nil {
+			result.Description = fmt.Sprintf("Cookie %s not set", cookieName)
+			return result
+		}
+
+		missing := []string{}
+		if requireSecure && !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+		if requireHTTPOnly && !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+		if sameSite != 0 && cookie.SameSite != sameSite {
+			missing = append(missing, fmt.Sprintf("SameSite=%s (%s)", sameSiteNames[sameSite], sameSiteNames[cookie.SameSite]))
+		}
+		if len(missing) > 0 {
+			result.Description = fmt.Sprintf("Cookie %s without %s", cookieName, strings.Join(missing, ", "))
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
 }
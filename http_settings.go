@@ -0,0 +1,665 @@
+package gochecks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"weak"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/net/proxy"
+)
+
+// DefaultMonitoringUserAgent User-Agent header sent by default by all the HTTP checkers
+const DefaultMonitoringUserAgent = "gochecks/1.0"
+
+// httpSettings settings used by the HTTP checkers to build the clients and requests. They can be changed using the HTTP
+// check modifiers (WithUserAgent, ...)
+type httpSettings struct {
+	userAgent      string
+	acceptEncoding string
+	proxy          *url.URL
+	serverIP       string
+	serverName     string
+	minTLSVersion  uint16
+	// ip addresses to use instead of resolving some hostnames
+	hosts map[string]string
+
+	cacheBusterName  string
+	cacheBusterValue func() string
+
+	disableKeepAlives     bool
+	idleConnTimeout       time.Duration
+	responseHeaderTimeout time.Duration
+	sharedClient          *http.Client
+
+	maxResponseSize int64
+	minResponseSize int64
+	debugLog        io.Writer
+
+	// transport built from the settings when the check function is created
+	transport http.RoundTripper
+	// context of the requests, set when the check is executed as a CheckFunctionCtx
+	ctx context.Context
+}
+
+// defaultHTTPSettings returns the settings of the HTTP checkers before applying any HTTP check modifier
+func defaultHTTPSettings() httpSettings {
+	return httpSettings{userAgent: DefaultMonitoringUserAgent}
+}
+
+// newTransport returns the http transport to use with the settings
+func (s httpSettings) newTransport() http.RoundTripper {
+	var transport http.RoundTripper = http.DefaultTransport
+	if s.sharedClient != nil {
+		if s.sharedClient.Transport != nil {
+			transport = s.sharedClient.Transport
+		}
+	} else if s.proxy != nil || s.serverIP != "" || s.serverName != "" || s.minTLSVersion != 0 || len(s.hosts) > 0 || s.disableKeepAlives ||
+		s.idleConnTimeout != 0 || s.responseHeaderTimeout != 0 {
+		transport = s.newHTTPTransport()
+	}
+	if s.acceptEncoding != "" {
+		transport = decodingTransport{next: transport}
+	}
+	return transport
+}
+
+// newHTTPTransport returns a new http.Transport, with the same defaults as http.DefaultTransport, configured with the
+// settings
+func (s httpSettings) newHTTPTransport() *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		DisableKeepAlives:     s.disableKeepAlives,
+		ResponseHeaderTimeout: s.responseHeaderTimeout,
+	}
+	if s.idleConnTimeout != 0 {
+		transport.IdleConnTimeout = s.idleConnTimeout
+	}
+	if s.serverIP != "" || len(s.hosts) > 0 {
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			if ip, ok := s.hosts[strings.ToLower(host)]; ok {
+				host = ip
+			} else if s.serverIP != "" {
+				host = s.serverIP
+			}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(host, port))
+		}
+	}
+	if s.serverName != "" || s.minTLSVersion != 0 {
+		transport.TLSClientConfig = &tls.Config{ServerName: s.serverName, MinVersion: s.minTLSVersion}
+	}
+	if s.proxy != nil {
+		transport.Proxy = http.ProxyURL(s.proxy)
+		if s.proxy.Scheme == "socks5" {
+			// the proxy url is validated by parseProxyURL
+			socks, _ := proxy.FromURL(s.proxy, dialer)
+			transport.Proxy = nil
+			transport.DialContext = socks.(proxy.ContextDialer).DialContext
+		}
+	}
+	return transport
+}
+
+// parseProxyURL parse and validate a proxy url, with http, https (CONNECT proxies) or socks5 scheme
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "socks5":
+		_, err = proxy.FromURL(u, proxy.Direct)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported proxy scheme %s", u.Scheme)
+	}
+	return u, nil
+}
+
+// client returns a http client configured with the settings and the given timeout (0 means no timeout)
+func (s httpSettings) client(timeout time.Duration) *http.Client {
+	if s.sharedClient != nil {
+		client := *s.sharedClient
+		client.Transport = s.transport
+		if timeout != 0 {
+			client.Timeout = timeout
+		}
+		return &client
+	}
+	return &http.Client{Timeout: timeout, Transport: s.transport}
+}
+
+// noRedirectClient returns a http client like client but that does not follow redirects
+func (s httpSettings) noRedirectClient(timeout time.Duration) *http.Client {
+	client := s.client(timeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return client
+}
+
+// newRequest returns a http request configured with the settings
+func (s httpSettings) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if s.ctx != nil {
+		request = request.WithContext(s.ctx)
+	}
+	if s.cacheBusterValue != nil {
+		query := request.URL.Query()
+		query.Set(s.cacheBusterName, s.cacheBusterValue())
+		request.URL.RawQuery = query.Encode()
+	}
+	request.Header.Set("User-Agent", s.userAgent)
+	if s.serverName != "" {
+		request.Host = s.serverName
+	}
+	if s.acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", s.acceptEncoding)
+	}
+	return request, nil
+}
+
+// get performs a GET request of the given url using the given client
+func (s httpSettings) get(client *http.Client, url string) (*http.Response, error) {
+	request, err := s.newRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(request)
+}
+
+// httpCheck a check performed by a HTTP checker with the given settings. It must only be referenced by its check
+// function, so it is garbage collected with it
+type httpCheck struct {
+	settings httpSettings
+	check    func(s httpSettings) Event
+}
+
+// httpChecks keep a weak pointer to the httpCheck of every check function returned by the HTTP checkers, by the address
+// of the function value, so the HTTP check modifiers can build a new check function with different settings. The
+// entries are removed when the check functions are garbage collected
+var httpChecks = struct {
+	sync.Mutex
+	checks map[uintptr]weak.Pointer[httpCheck]
+}{checks: map[uintptr]weak.Pointer[httpCheck]{}}
+
+// newHTTPCheck returns a check function that invoke the given check with the default http settings
+func newHTTPCheck(check func(s httpSettings) Event) CheckFunction {
+	return newRegisteredHTTPCheck(defaultHTTPSettings(), check)
+}
+
+// newRegisteredHTTPCheck returns a check function that invoke the given check with the given settings, registered in
+// httpChecks
+func newRegisteredHTTPCheck(settings httpSettings, check func(s httpSettings) Event) CheckFunction {
+	settings.transport = settings.newTransport()
+	c := &httpCheck{settings: settings, check: check}
+	f := CheckFunction(func() Event {
+		return c.settings.run(c.check)
+	})
+	id := f.id()
+	httpChecks.Lock()
+	httpChecks.checks[id] = weak.Make(c)
+	httpChecks.Unlock()
+	runtime.AddCleanup(c, unregisterHTTPCheck, id)
+	return f
+}
+
+// unregisterHTTPCheck removes the entry of a garbage collected httpCheck, unless the address was already reused by a
+// new check function
+func unregisterHTTPCheck(id uintptr) {
+	httpChecks.Lock()
+	defer httpChecks.Unlock()
+	if httpChecks.checks[id].Value() == nil {
+		delete(httpChecks.checks, id)
+	}
+}
+
+// id returns the address of the function value, that identifies each check function
+func (f CheckFunction) id() uintptr {
+	return uintptr(*(*unsafe.Pointer)(unsafe.Pointer(&f)))
+}
+
+// httpCheck returns the httpCheck of a check function returned by a HTTP checker, or nil for any other check function
+func (f CheckFunction) httpCheck() *httpCheck {
+	if f == nil {
+		return nil
+	}
+	httpChecks.Lock()
+	defer httpChecks.Unlock()
+	return httpChecks.checks[f.id()].Value()
+}
+
+// withHTTPSettings returns a new check function with the http settings changed by the given modify function when the
+// check function was returned by a HTTP checker. Otherwise returns the check function unchanged
+func (f CheckFunction) withHTTPSettings(modify func(s *httpSettings)) CheckFunction {
+	c := f.httpCheck()
+	if c == nil {
+		return f
+	}
+	settings := c.settings
+	modify(&settings)
+	return newRegisteredHTTPCheck(settings, c.check)
+}
+
+// run executes the check with the settings
+func (s httpSettings) run(check func(s httpSettings) Event) Event {
+	if s.maxResponseSize == 0 && s.minResponseSize == 0 && s.debugLog == nil {
+		return check(s)
+	}
+	// every execution uses its own transports to know the size of its responses and record them
+	callSettings := s
+	var debug *debugTransport
+	if s.debugLog != nil {
+		debug = &debugTransport{next: callSettings.transport}
+		callSettings.transport = debug
+	}
+	var sizes *responseSizeTransport
+	if s.maxResponseSize != 0 || s.minResponseSize != 0 {
+		sizes = &responseSizeTransport{next: callSettings.transport, max: s.maxResponseSize, min: s.minResponseSize}
+		callSettings.transport = sizes
+	}
+
+	result := check(callSettings)
+	if sizes != nil {
+		truncated, short, shortSize := sizes.status()
+		if short {
+			result.State = StateCritical
+			result.Description = strings.TrimSpace(fmt.Sprintf("%s Response of %d bytes, expected at least %d", result.Description, shortSize, s.minResponseSize))
+		} else if truncated {
+			result.State = StateWarning
+			result.Description = strings.TrimSpace(fmt.Sprintf("%s Response truncated to %d bytes", result.Description, s.maxResponseSize))
+		}
+	}
+	if debug != nil && result.State != StateOK {
+		debug.write(s.debugLog, result)
+	}
+	return result
+}
+
+// newHTTPMultiCheck returns a multi check function that invoke the given check with the given http settings
+func newHTTPMultiCheck(settings httpSettings, check func(s httpSettings) []Event) MultiCheckFunction {
+	settings.transport = settings.newTransport()
+	return func() []Event {
+		return check(settings)
+	}
+}
+
+// WithUserAgent returns a new check function that send the given User-Agent header in the http requests.
+// As the rest of HTTP check modifiers, only works when applied directly to a check function returned by a HTTP checker
+// (before Tags, Retry...), any other check function is returned unchanged
+func (f CheckFunction) WithUserAgent(ua string) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.userAgent = ua
+	})
+}
+
+// AcceptEncoding returns a new check function that send the given Accept-Encoding header (gzip, deflate or br) in the
+// http requests and decompress the responses, so the checks validate the uncompressed body
+func (f CheckFunction) AcceptEncoding(encoding string) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.acceptEncoding = encoding
+	})
+}
+
+// WithKeepAlive returns a new check function that reuse (enable true) or not (enable false) the http connections
+// between executions. Without keep alive every execution measures the latency of a new connection
+func (f CheckFunction) WithKeepAlive(enable bool) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.disableKeepAlives = !enable
+	})
+}
+
+// WithConnectionReuse returns a new check function that perform the http requests using the connections of the given
+// client, so they can be shared by several checks. The transport of the client is used instead of the one configured by
+// other modifiers (WithKeepAlive...)
+func (f CheckFunction) WithConnectionReuse(pool *http.Client) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.sharedClient = pool
+	})
+}
+
+// WithIdleConnTimeout returns a new check function that close the idle http connections after the given duration
+func (f CheckFunction) WithIdleConnTimeout(d time.Duration) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.idleConnTimeout = d
+	})
+}
+
+// WithResponseHeaderTimeout returns a new check function that fail when the response headers are not received in the
+// given duration after the request is sent
+func (f CheckFunction) WithResponseHeaderTimeout(d time.Duration) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.responseHeaderTimeout = d
+	})
+}
+
+// BypassCache returns a new check function that add a query parameter "_" with the current timestamp to the url of
+// every http request, so the responses are not served by a cache (CDN, proxy...)
+func (f CheckFunction) BypassCache() CheckFunction {
+	return f.BypassCacheWith("_", TimestampCacheBuster)
+}
+
+// BypassCacheWith returns a new check function that add a query parameter with the given name and a value obtained from
+// the given function (TimestampCacheBuster, RandomCacheBuster...) to the url of every http request
+func (f CheckFunction) BypassCacheWith(name string, value func() string) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.cacheBusterName = name
+		s.cacheBusterValue = value
+	})
+}
+
+// TimestampCacheBuster returns the current unix time in nanoseconds, to be used with BypassCacheWith
+func TimestampCacheBuster() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// RandomCacheBuster returns a random hexadecimal string, to be used with BypassCacheWith
+func RandomCacheBuster() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithDNSOverride returns a new check function that connect to the given ip when a http request is made to the given
+// hostname, without using the resolver (like curl --resolve). The Host header and the TLS server name are not changed
+func (f CheckFunction) WithDNSOverride(hostname, ip string) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		hosts := map[string]string{}
+		for h, i := range s.hosts {
+			hosts[h] = i
+		}
+		hosts[strings.ToLower(hostname)] = ip
+		s.hosts = hosts
+	})
+}
+
+// WithMaxResponseSize returns a new check function that read at most the given bytes of every http response body, to
+// protect the memory. When a response is truncated the state is "warning" and the metric is not changed
+func (f CheckFunction) WithMaxResponseSize(bytes int64) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.maxResponseSize = bytes
+	})
+}
+
+// WithMinResponseSize returns a new check function that read the full http response bodies and set the state to
+// "critical" when some of them has less than the given bytes, to detect truncated responses
+func (f CheckFunction) WithMinResponseSize(bytes int64) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.minResponseSize = bytes
+	})
+}
+
+// responseSizeTransport http transport that limit the size of the response bodies (max, when not 0) and record if some
+// of them was truncated or had less than min bytes
+type responseSizeTransport struct {
+	next http.RoundTripper
+	max  int64
+	min  int64
+
+	mutex     sync.Mutex
+	truncated bool
+	short     bool
+	shortSize int64
+}
+
+func (t *responseSizeTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = &sizedBody{body: response.Body, transport: t}
+	return response, nil
+}
+
+func (t *responseSizeTransport) status() (truncated, short bool, shortSize int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.truncated, t.short, t.shortSize
+}
+
+// sizedBody response body that count the bytes read and returns EOF after reading the transport max, recording in the
+// transport if the body had more data. When closed the rest of the body is read to check the min size
+type sizedBody struct {
+	body      io.ReadCloser
+	read      int64
+	eof       bool
+	transport *responseSizeTransport
+}
+
+func (b *sizedBody) Read(p []byte) (int, error) {
+	max := b.transport.max
+	if max > 0 && b.read >= max {
+		if !b.eof {
+			var extra [1]byte
+			if n, _ := b.body.Read(extra[:]); n > 0 {
+				b.transport.mutex.Lock()
+				b.transport.truncated = true
+				b.transport.mutex.Unlock()
+			}
+			b.eof = true
+		}
+		return 0, io.EOF
+	}
+	if max > 0 && int64(len(p)) > max-b.read {
+		p = p[:max-b.read]
+	}
+	n, err := b.body.Read(p)
+	b.read += int64(n)
+	if err == io.EOF {
+		b.eof = true
+	}
+	return n, err
+}
+
+func (b *sizedBody) Close() error {
+	if b.transport.min > 0 {
+		if !b.eof {
+			io.Copy(ioutil.Discard, b)
+		}
+		if b.read < b.transport.min {
+			b.transport.mutex.Lock()
+			b.transport.short = true
+			b.transport.shortSize = b.read
+			b.transport.mutex.Unlock()
+		}
+	}
+	return b.body.Close()
+}
+
+// DebugLog returns a new check function that write to w the http requests (method, url and headers) and responses
+// (status, headers and the first 4096 bytes of the body) performed by the executions that are not "ok". The values of
+// the Authorization and Cookie headers are redacted
+func (f CheckFunction) DebugLog(w io.Writer) CheckFunction {
+	return f.withHTTPSettings(func(s *httpSettings) {
+		s.debugLog = w
+	})
+}
+
+// maxDebugBodySize max bytes of every response body written by DebugLog
+const maxDebugBodySize = 4096
+
+// debugLogMutex serialize the writes of DebugLog, so the logs of several executions are not mixed
+var debugLogMutex sync.Mutex
+
+// redactedHeaders headers whose values are not written by DebugLog
+var redactedHeaders = map[string]bool{"Authorization": true, "Proxy-Authorization": true, "Cookie": true, "Set-Cookie": true}
+
+// debugTransport http transport that record the requests and responses, with the first bytes of the bodies read
+type debugTransport struct {
+	next http.RoundTripper
+
+	mutex     sync.Mutex
+	exchanges []*debugExchange
+}
+
+type debugExchange struct {
+	request  bytes.Buffer
+	response bytes.Buffer
+	body     bytes.Buffer
+}
+
+func (t *debugTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	exchange := &debugExchange{}
+	fmt.Fprintf(&exchange.request, "> %s %s\n", request.Method, request.URL)
+	writeDebugHeaders(&exchange.request, "> ", request.Header)
+	t.mutex.Lock()
+	t.exchanges = append(t.exchanges, exchange)
+	t.mutex.Unlock()
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		fmt.Fprintf(&exchange.response, "< %s\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(&exchange.response, "< %s %s\n", response.Proto, response.Status)
+	writeDebugHeaders(&exchange.response, "< ", response.Header)
+	response.Body = &debugBody{body: response.Body, exchange: exchange, transport: t}
+	return response, nil
+}
+
+func writeDebugHeaders(w io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if redactedHeaders[name] {
+				value = "[REDACTED]"
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+// write writes the recorded requests and responses of the result to w
+func (t *debugTransport) write(w io.Writer, result Event) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	debugLogMutex.Lock()
+	defer debugLogMutex.Unlock()
+
+	fmt.Fprintf(w, "--- %s %s %s %s\n", result.Host, result.Service, result.State, result.Description)
+	for _, exchange := range t.exchanges {
+		w.Write(exchange.request.Bytes())
+		w.Write(exchange.response.Bytes())
+		if exchange.body.Len() > 0 {
+			w.Write(exchange.body.Bytes())
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// debugBody response body that record the first bytes read. When closed the bytes not read are recorded too
+type debugBody struct {
+	body      io.ReadCloser
+	exchange  *debugExchange
+	transport *debugTransport
+}
+
+func (b *debugBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.record(p[:n])
+	return n, err
+}
+
+func (b *debugBody) record(p []byte) {
+	b.transport.mutex.Lock()
+	defer b.transport.mutex.Unlock()
+	if remaining := maxDebugBodySize - b.exchange.body.Len(); remaining > 0 {
+		if len(p) > remaining {
+			p = p[:remaining]
+		}
+		b.exchange.body.Write(p)
+	}
+}
+
+func (b *debugBody) Close() error {
+	b.transport.mutex.Lock()
+	remaining := maxDebugBodySize - b.exchange.body.Len()
+	b.transport.mutex.Unlock()
+	if remaining > 0 {
+		rest, _ := ioutil.ReadAll(io.LimitReader(b.body, int64(remaining)))
+		b.record(rest)
+	}
+	return b.body.Close()
+}
+
+// decodingTransport http transport that decompress the responses (the requests set the Accept-Encoding header)
+type decodingTransport struct {
+	next http.RoundTripper
+}
+
+func (t decodingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	switch strings.ToLower(response.Header.Get("Content-Encoding")) {
+	case "gzip":
+		body, err = gzip.NewReader(response.Body)
+		if err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+	case "deflate":
+		body, err = zlib.NewReader(response.Body)
+		if err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+	case "br":
+		body = brotli.NewReader(response.Body)
+	default:
+		return response, nil
+	}
+	response.Body = decodedBody{Reader: body, Closer: response.Body}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return response, nil
+}
+
+type decodedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,83 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"net/textproto"
+)
+
+// NewIMAPCheck returns a check function that connect to a IMAP server, wait for the greeting and login with the given
+// credentials. The metric is the time from the connection to the successful login in milliseconds
+func NewIMAPCheck(host, service, addr, username, password string, timeout time.Duration) CheckFunction {
+	return NewIMAPCheckCtx(host, service, addr, username, password, timeout).CheckFunction()
+}
+
+// NewIMAPCheckCtx returns a CheckFunctionCtx like NewIMAPCheck that honor the context
+func NewIMAPCheckCtx(host, service, addr, username, password string, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		dialer := &net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		defer conn.Close()
+		defer context.AfterFunc(ctx, func() { conn.Close() })()
+		conn.SetDeadline(time.Now().Add(timeout))
+		text := textproto.NewConn(conn)
+
+		greeting, err := text.ReadLine()
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		if !strings.HasPrefix(greeting, "* OK") {
+			result.Description = fmt.Sprintf("Unexpected greeting: %s", greeting)
+			return result
+		}
+
+		response, err := imapCommand(text, "a1", "LOGIN "+imapQuote(username)+" "+imapQuote(password))
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		if !strings.HasPrefix(response, "a1 OK") {
+			result.Description = fmt.Sprintf("Login failed: %s", response)
+			return result
+		}
+		imapCommand(text, "a2", "LOGOUT")
+		result.State = StateOK
+		return result
+	}
+}
+
+// imapCommand send a tagged command and return the tagged response line
+func imapCommand(text *textproto.Conn, tag, command string) (string, error) {
+	err := text.PrintfLine("%s %s", tag, command)
+	if err != nil {
+		return "", err
+	}
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			return line, nil
+		}
+	}
+}
+
+func imapQuote(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return `"` + value + `"`
+}
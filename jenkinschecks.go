@@ -7,7 +7,6 @@ import (
 
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 )
 
 // JobStatus jenkins job status info
@@ -25,27 +24,27 @@ type JobsMessage struct {
 // jobs matching the given jobRegExp are ok (blue color). When one or more of these jobs are in error the event is critical
 // and the jobs names are included at the event description
 func NewJenkinsJobsChecker(host, service, jenkinsBaseURL string, jobRegExp string) CheckFunction {
-	return func() Event {
+	return newHTTPCheck(func(s httpSettings) Event {
 
 		brokenJobs := []string{}
 
-		response, err := http.Get(jenkinsBaseURL + "api/json?tree=jobs[name,color]")
+		response, err := s.get(s.client(0), jenkinsBaseURL+"api/json?tree=jobs[name,color]")
 		if err != nil {
-			return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 		}
 		if response.StatusCode != 200 {
-			return Event{Host: host, Service: service, State: "critical", Description: fmt.Sprintf("Response %d", response.StatusCode)}
+			return Event{Host: host, Service: service, State: StateCritical, Description: fmt.Sprintf("Response %d", response.StatusCode)}
 		}
 		if response.Body == nil {
-			return Event{Host: host, Service: service, State: "critical", Description: fmt.Sprintf("Empty body")}
+			return Event{Host: host, Service: service, State: StateCritical, Description: fmt.Sprintf("Empty body")}
 		}
 
 		body, err := ioutil.ReadAll(response.Body)
 		if err != nil {
-			return Event{Host: host, Service: service, State: "critical", Description: fmt.Sprintf("Error geting body")}
+			return Event{Host: host, Service: service, State: StateCritical, Description: fmt.Sprintf("Error geting body")}
 		}
 
-		state := "ok"
+		state := StateOK
 		jobsOk := 0
 		var jobs JobsMessage
 		err = json.Unmarshal(body, &jobs)
@@ -54,7 +53,7 @@ func NewJenkinsJobsChecker(host, service, jenkinsBaseURL string, jobRegExp strin
 				matched, _ := regexp.MatchString(jobRegExp, job.Name)
 				if matched {
 					if !strings.HasPrefix(job.Color, "blue") {
-						state = "critical"
+						state = StateCritical
 						brokenJobs = append(brokenJobs, job.Name)
 					} else {
 						jobsOk = jobsOk + 1
@@ -63,7 +62,7 @@ func NewJenkinsJobsChecker(host, service, jenkinsBaseURL string, jobRegExp strin
 			}
 			return Event{Host: host, Service: service, State: state, Description: strings.Join(brokenJobs, ","), Metric: jobsOk}
 		}
-		return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
-	}
+		return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+	})
 
 }
@@ -0,0 +1,34 @@
+package gochecks
+
+import (
+	"strconv"
+	"strings"
+)
+
+// jsonPathValue returns the value of a decoded json document at the given path. The path is a list of object keys or
+// array indexes separated by dots (for example "data.items.0.name")
+func jsonPathValue(document interface{}, path string) (interface{}, bool) {
+	value := document
+	if path == "" {
+		return value, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
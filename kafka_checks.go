@@ -0,0 +1,107 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaTimeout timeout of the Kafka requests
+const kafkaTimeout = 10 * time.Second
+
+// NewKafkaConsumerLagChecker returns a check function that calculate the lag of a consumer group for a topic (the
+// messages between the committed offset and the last offset of every partition) and return the total lag as metric.
+// The partitions without committed offset count all their messages. The state is critical when the lag is greater
+// than maxLag
+func NewKafkaConsumerLagChecker(host, service string, brokers []string, group, topic string, maxLag int64) CheckFunction {
+	return NewKafkaConsumerLagCheckerCtx(host, service, brokers, group, topic, maxLag).CheckFunction()
+}
+
+// NewKafkaConsumerLagCheckerCtx returns a CheckFunctionCtx like NewKafkaConsumerLagChecker that honor the context
+func NewKafkaConsumerLagCheckerCtx(host, service string, brokers []string, group, topic string, maxLag int64) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		lag, err := kafkaConsumerLag(ctx, brokers, group, topic)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		result.Metric = float32(lag)
+		result.Description = fmt.Sprintf("Lag %d of %s in %s (max %d)", lag, group, topic, maxLag)
+		if lag <= maxLag {
+			result.State = StateOK
+		}
+		return result
+	}
+}
+
+// kafkaConsumerLag returns the total lag of a consumer group in all the partitions of a topic
+func kafkaConsumerLag(ctx context.Context, brokers []string, group, topic string) (int64, error) {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...), Timeout: kafkaTimeout}
+	ctx, cancel := context.WithTimeout(ctx, kafkaTimeout)
+	defer cancel()
+
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return 0, err
+	}
+	if len(metadata.Topics) != 1 {
+		return 0, fmt.Errorf("Topic %s not found", topic)
+	}
+	if metadata.Topics[0].Error != nil {
+		return 0, metadata.Topics[0].Error
+	}
+	partitions := []int{}
+	lastOffsetRequests := []kafka.OffsetRequest{}
+	firstOffsetRequests := []kafka.OffsetRequest{}
+	for _, partition := range metadata.Topics[0].Partitions {
+		partitions = append(partitions, partition.ID)
+		lastOffsetRequests = append(lastOffsetRequests, kafka.LastOffsetOf(partition.ID))
+		firstOffsetRequests = append(firstOffsetRequests, kafka.FirstOffsetOf(partition.ID))
+	}
+
+	offsets, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: append(lastOffsetRequests, firstOffsetRequests...)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	firstOffsets := map[int]int64{}
+	lastOffsets := map[int]int64{}
+	for _, partitionOffsets := range offsets.Topics[topic] {
+		if partitionOffsets.Error != nil {
+			return 0, partitionOffsets.Error
+		}
+		firstOffsets[partitionOffsets.Partition] = partitionOffsets.FirstOffset
+		lastOffsets[partitionOffsets.Partition] = partitionOffsets.LastOffset
+	}
+
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: group,
+		Topics:  map[string][]int{topic: partitions},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if committed.Error != nil {
+		return 0, committed.Error
+	}
+
+	var lag int64
+	for _, partition := range committed.Topics[topic] {
+		if partition.Error != nil {
+			return 0, partition.Error
+		}
+		offset := partition.CommittedOffset
+		if offset < 0 {
+			offset = firstOffsets[partition.Partition]
+		}
+		if last := lastOffsets[partition.Partition]; last > offset {
+			lag += last - offset
+		}
+	}
+	return lag, nil
+}
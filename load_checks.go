@@ -0,0 +1,55 @@
+package gochecks
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"io/ioutil"
+)
+
+const loadavgPath = "/proc/loadavg"
+
+// NewLoadAverageChecker returns a check function that return the 1, 5 or 15 minutes (period) load average divided by
+// the number of CPUs as metric, so the same thresholds can be used in machines of different sizes (ex: 1 means all the
+// CPUs busy). Only supported in linux
+func NewLoadAverageChecker(host, service string, period int) CheckFunction {
+	return func() Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var field int
+		switch period {
+		case 1:
+			field = 0
+		case 5:
+			field = 1
+		case 15:
+			field = 2
+		default:
+			result.Description = fmt.Sprintf("Invalid load average period %d (1, 5 or 15)", period)
+			return result
+		}
+
+		content, err := ioutil.ReadFile(loadavgPath)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		fields := strings.Fields(string(content))
+		if len(fields) < 3 {
+			result.Description = fmt.Sprintf("Invalid %s content", loadavgPath)
+			return result
+		}
+		load, err := strconv.ParseFloat(fields[field], 64)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		cpus := runtime.NumCPU()
+		result.Metric = float32(load / float64(cpus))
+		result.Description = fmt.Sprintf("Load %.2f (%d minutes) with %d CPUs", load, period, cpus)
+		result.State = StateOK
+		return result
+	}
+}
@@ -0,0 +1,74 @@
+package gochecks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const meminfoPath = "/proc/meminfo"
+
+// NewMemoryUsageChecker returns a check function that return the percentage of used memory (not available for new
+// processes without swapping, as MemAvailable in /proc/meminfo) as metric, to be used with the threshold modifiers.
+// Only supported in linux
+func NewMemoryUsageChecker(host, service string) CheckFunction {
+	return meminfoUsageChecker(host, service, "MemTotal", "MemAvailable", "memory")
+}
+
+// NewSwapUsageChecker returns a check function that return the percentage of used swap as metric, to be used with the
+// threshold modifiers. Without swap the metric is 0. Only supported in linux
+func NewSwapUsageChecker(host, service string) CheckFunction {
+	return meminfoUsageChecker(host, service, "SwapTotal", "SwapFree", "swap")
+}
+
+// meminfoUsageChecker returns a check function that return the used percentage of a /proc/meminfo total field
+func meminfoUsageChecker(host, service, totalField, freeField, name string) CheckFunction {
+	return func() Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		meminfo, err := readMeminfo(meminfoPath)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		total, okTotal := meminfo[totalField]
+		free, okFree := meminfo[freeField]
+		if !okTotal || !okFree {
+			result.Description = fmt.Sprintf("%s or %s not found in %s", totalField, freeField, meminfoPath)
+			return result
+		}
+		result.Metric = float32(0)
+		if total > 0 && free <= total {
+			result.Metric = float32(float64(total-free) * 100 / float64(total))
+		}
+		result.Description = fmt.Sprintf("%.1f%% %s used of %d kB", result.Metric, name, total)
+		result.State = StateOK
+		return result
+	}
+}
+
+// readMeminfo returns the fields of a /proc/meminfo file, in kB
+func readMeminfo(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	meminfo := map[string]uint64{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		meminfo[strings.TrimSuffix(fields[0], ":")] = value
+	}
+	return meminfo, scanner.Err()
+}
@@ -0,0 +1,39 @@
+package gochecks
+
+// metricValue returns the numeric value of a event metric, that can be any integer or float type
+func metricValue(metric interface{}) (float64, bool) {
+	switch value := metric.(type) {
+	case float32:
+		return float64(value), true
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	case int8:
+		return float64(value), true
+	case int16:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint8:
+		return float64(value), true
+	case uint16:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	}
+	return 0, false
+}
+
+// MetricFloat32 returns the metric of the event as float32, converting any integer or float type. Returns false when
+// the metric is nil or is not numeric
+func (e Event) MetricFloat32() (float32, bool) {
+	value, ok := metricValue(e.Metric)
+	return float32(value), ok
+}
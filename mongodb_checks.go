@@ -0,0 +1,129 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	mongoDBTimeout = 10 * time.Second
+)
+
+// NewMongoDBChecker returns a check function that connect to a mongodb node and validate that it is a primary (or a
+// standalone server) or a secondary. The time to connect and get the node status is returned as metric
+func NewMongoDBChecker(host, service, uri string) CheckFunction {
+	return NewMongoDBCheckerCtx(host, service, uri).CheckFunction()
+}
+
+// NewMongoDBCheckerCtx returns a CheckFunctionCtx like NewMongoDBChecker that honor the context
+func NewMongoDBCheckerCtx(host, service, uri string) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		ctx, cancel := context.WithTimeout(ctx, mongoDBTimeout)
+		defer cancel()
+
+		var t1 = time.Now()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetDirect(true))
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		defer client.Disconnect(context.Background())
+
+		var status struct {
+			IsMaster  bool   `bson:"ismaster"`
+			Secondary bool   `bson:"secondary"`
+			SetName   string `bson:"setName"`
+		}
+		err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&status)
+		milliseconds := float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error(), Metric: milliseconds}
+		}
+		switch {
+		case status.IsMaster:
+			return Event{Host: host, Service: service, State: StateOK, Description: "PRIMARY", Metric: milliseconds}
+		case status.Secondary:
+			return Event{Host: host, Service: service, State: StateOK, Description: "SECONDARY", Metric: milliseconds}
+		}
+		return Event{Host: host, Service: service, State: StateCritical, Description: "Not PRIMARY or SECONDARY", Metric: milliseconds}
+	}
+}
+
+// replicaSetStatus the fields used of the replSetGetStatus command response
+type replicaSetStatus struct {
+	Members []struct {
+		Name       string    `bson:"name"`
+		StateStr   string    `bson:"stateStr"`
+		OptimeDate time.Time `bson:"optimeDate"`
+	} `bson:"members"`
+}
+
+// NewMongoDBReplicaSetCheck returns a check function that get the status of a mongodb replica set and return as metric
+// the max replication lag (seconds) of the secondaries. The state is "critical" when there is no primary or the lag of
+// some secondary is greater or equal than maxLagSeconds
+func NewMongoDBReplicaSetCheck(host, service, uri string, maxLagSeconds int, timeout time.Duration) CheckFunction {
+	return NewMongoDBReplicaSetCheckCtx(host, service, uri, maxLagSeconds, timeout).CheckFunction()
+}
+
+// NewMongoDBReplicaSetCheckCtx returns a CheckFunctionCtx like NewMongoDBReplicaSetCheck that honor the context
+func NewMongoDBReplicaSetCheckCtx(host, service, uri string, maxLagSeconds int, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer client.Disconnect(context.Background())
+
+		var status replicaSetStatus
+		err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(&status)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+
+		var primary time.Time
+		hasPrimary := false
+		for _, member := range status.Members {
+			if member.StateStr == "PRIMARY" {
+				primary = member.OptimeDate
+				hasPrimary = true
+			}
+		}
+		if !hasPrimary {
+			result.Description = "No primary"
+			return result
+		}
+
+		var maxLag time.Duration
+		lagging := []string{}
+		for _, member := range status.Members {
+			if member.StateStr != "SECONDARY" {
+				continue
+			}
+			lag := primary.Sub(member.OptimeDate)
+			if lag > maxLag {
+				maxLag = lag
+			}
+			if lag >= time.Duration(maxLagSeconds)*time.Second {
+				lagging = append(lagging, member.Name)
+			}
+		}
+		result.Metric = float32(maxLag.Seconds())
+		if len(lagging) > 0 {
+			result.Description = fmt.Sprintf("Secondaries lagging: %s", strings.Join(lagging, ","))
+			return result
+		}
+		result.State = StateOK
+		return result
+	}
+}
@@ -0,0 +1,89 @@
+package gochecks
+
+import (
+	"time"
+)
+
+// eachEvent returns a new multi check function that apply the given check function modifier to every event returned by
+// the initial multi check function
+func (f MultiCheckFunction) eachEvent(modifier func(CheckFunction) CheckFunction) MultiCheckFunction {
+	return func() []Event {
+		results := f()
+		for i := range results {
+			event := results[i]
+			results[i] = modifier(func() Event { return event })()
+		}
+		return results
+	}
+}
+
+// Tags returns a new multi check function that adds the given tags to every result generated by the initial multi check
+// function
+func (f MultiCheckFunction) Tags(tags ...string) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.Tags(tags...) })
+}
+
+// Attributes returns a new multi check function that adds the attributes map to every result generated by the initial
+// multi check function
+func (f MultiCheckFunction) Attributes(attributes map[string]string) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.Attributes(attributes) })
+}
+
+// TTL returns a new multi check function that adds the given TTL time (in seconds) to every result generated by the
+// initial multi check function
+func (f MultiCheckFunction) TTL(ttl float32) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.TTL(ttl) })
+}
+
+// WithAnnotation returns a new multi check function that adds the given annotation to every result generated by the
+// initial multi check function
+func (f MultiCheckFunction) WithAnnotation(key, value string) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.WithAnnotation(key, value) })
+}
+
+// Retry returns a new multi check function that execute the given function up to a given retry times or until the
+// first execution where all the results are ok (whichever comes first). The new function will return the events of the
+// last execution
+func (f MultiCheckFunction) Retry(times int, sleep time.Duration) MultiCheckFunction {
+	return func() []Event {
+		var results []Event
+		for i := 0; i < times; i++ {
+			results = f()
+			allOk := true
+			for _, result := range results {
+				if result.State != StateOK {
+					allOk = false
+				}
+			}
+			if allOk {
+				return results
+			}
+			time.Sleep(sleep)
+		}
+		return results
+	}
+}
+
+// CriticalIfLessThan returns a new multi check function that change the state of every result to "critical" when its
+// metric is less than a threadshold and is not already "critical"
+func (f MultiCheckFunction) CriticalIfLessThan(threshold float32) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.CriticalIfLessThan(threshold) })
+}
+
+// CriticalIfGreaterThan returns a new multi check function that change the state of every result to "critical" when
+// its metric is greater than a threadshold and is not already "critical"
+func (f MultiCheckFunction) CriticalIfGreaterThan(threshold float32) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.CriticalIfGreaterThan(threshold) })
+}
+
+// WarningIfLessThan returns a new multi check function that change the state of every result to "warning" when its
+// metric is less than a threadshold and is not already "critical"
+func (f MultiCheckFunction) WarningIfLessThan(threshold float32) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.WarningIfLessThan(threshold) })
+}
+
+// WarningIfGreaterThan returns a new multi check function that change the state of every result to "warning" when its
+// metric is greater than a threadshold and is not already "critical"
+func (f MultiCheckFunction) WarningIfGreaterThan(threshold float32) MultiCheckFunction {
+	return f.eachEvent(func(c CheckFunction) CheckFunction { return c.WarningIfGreaterThan(threshold) })
+}
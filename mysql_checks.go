@@ -0,0 +1,122 @@
+package gochecks
+
+import (
+	"context"
+	"time"
+
+	"database/sql"
+)
+
+// NewMySQLTableSizeCheck returns a check function that return as metric the disk usage (data and indexes, in megabytes)
+// of a given mysql table. Use the threshold modifiers (CriticalIfGreaterThan...) to validate the size
+func NewMySQLTableSizeCheck(host, service, mysqluri, database, table string) CheckFunction {
+	return NewMySQLTableSizeCheckCtx(host, service, mysqluri, database, table).CheckFunction()
+}
+
+// NewMySQLTableSizeCheckCtx returns a CheckFunctionCtx like NewMySQLTableSizeCheck that honor the context
+func NewMySQLTableSizeCheckCtx(host, service, mysqluri, database, table string) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		dsn, err := mysqlDSN(mysqluri)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		con, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		defer con.Close()
+
+		q := `SELECT data_length + index_length FROM information_schema.TABLES WHERE table_schema = ? AND table_name = ?`
+		var size float64
+		err = con.QueryRowContext(ctx, q, database, table).Scan(&size)
+		if err == sql.ErrNoRows {
+			return Event{Host: host, Service: service, State: StateCritical, Description: "Table " + database + "." + table + " not found"}
+		}
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		return Event{Host: host, Service: service, State: StateOK, Metric: float32(size / (1024 * 1024))}
+	}
+}
+
+// NewMySQLSlowQueryCheck returns a check function that return as metric the number of slow queries (Slow_queries global
+// status) of a mysql server since it was started
+func NewMySQLSlowQueryCheck(host, service, mysqluri string) CheckFunction {
+	return NewMySQLSlowQueryCheckCtx(host, service, mysqluri).CheckFunction()
+}
+
+// NewMySQLSlowQueryCheckCtx returns a CheckFunctionCtx like NewMySQLSlowQueryCheck that honor the context
+func NewMySQLSlowQueryCheckCtx(host, service, mysqluri string) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		dsn, err := mysqlDSN(mysqluri)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		con, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		defer con.Close()
+
+		var name string
+		var count float64
+		err = con.QueryRowContext(ctx, `SHOW GLOBAL STATUS LIKE 'Slow_queries'`).Scan(&name, &count)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		return Event{Host: host, Service: service, State: StateOK, Metric: float32(count)}
+	}
+}
+
+// MySQLConnectionPoolCheck check that get a connection from a mysql connection pool, kept between executions. The pool
+// must be closed with Close when the check is not used anymore
+type MySQLConnectionPoolCheck struct {
+	host    string
+	service string
+	maxWait time.Duration
+	db      *sql.DB
+}
+
+// NewMySQLConnectionPoolCheck returns a MySQLConnectionPoolCheck with a new connection pool to the given mysql uri. Its
+// Check method is the check function (ex: engine.AddCheck(poolCheck.Check, period))
+func NewMySQLConnectionPoolCheck(host, service, mysqluri string, maxWait time.Duration) (*MySQLConnectionPoolCheck, error) {
+	dsn, err := mysqlDSN(mysqluri)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &MySQLConnectionPoolCheck{host: host, service: service, maxWait: maxWait, db: db}, nil
+}
+
+// Check get a connection from the pool and ping the server. The wait time is returned as metric and the state is
+// "critical" when the ping is not done in maxWait
+func (c *MySQLConnectionPoolCheck) Check() Event {
+	return c.CheckCtx(context.Background())
+}
+
+// CheckCtx is the CheckFunctionCtx version of Check, the ping is also aborted when the context is done
+func (c *MySQLConnectionPoolCheck) CheckCtx(ctx context.Context) Event {
+	ctx, cancel := context.WithTimeout(ctx, c.maxWait)
+	defer cancel()
+
+	var t1 = time.Now()
+	err := c.db.PingContext(ctx)
+	milliseconds := float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+	if err != nil {
+		return Event{Host: c.host, Service: c.service, State: StateCritical, Description: err.Error(), Metric: milliseconds}
+	}
+	return Event{Host: c.host, Service: c.service, State: StateOK, Metric: milliseconds}
+}
+
+// DB returns the connection pool, to be used for example with NewDatabasePoolCheck
+func (c *MySQLConnectionPoolCheck) DB() *sql.DB {
+	return c.db
+}
+
+// Close close the connection pool
+func (c *MySQLConnectionPoolCheck) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,101 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"encoding/binary"
+)
+
+const (
+	ntpTimeout = 5 * time.Second
+	// ntpEpochOffset seconds between the NTP epoch (1900) and the unix epoch (1970)
+	ntpEpochOffset = 2208988800
+)
+
+// NewNTPChecker returns a check function that query a NTP server (host or host:port, port 123 by default) and return
+// the absolute offset of the local clock in milliseconds as metric, to alert on clock drift with the threshold
+// modifiers (ex: CriticalIfGreaterThan(500)). The signed offset is included in the description
+func NewNTPChecker(host, service, ntpServer string) CheckFunction {
+	return NewNTPCheckerCtx(host, service, ntpServer).CheckFunction()
+}
+
+// NewNTPCheckerCtx returns a CheckFunctionCtx like NewNTPChecker that honor the context
+func NewNTPCheckerCtx(host, service, ntpServer string) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		addr := ntpServer
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "123")
+		}
+		offset, stratum, err := ntpQuery(ctx, addr)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		if stratum == 0 {
+			result.Description = fmt.Sprintf("Unsynchronized server %s (stratum 0)", ntpServer)
+			return result
+		}
+		result.Metric = float32(math.Abs(float64(offset.Nanoseconds()) / 1e6))
+		result.Description = fmt.Sprintf("Offset %s (stratum %d)", offset, stratum)
+		result.State = StateOK
+		return result
+	}
+}
+
+// ntpQuery send a NTP v4 client request and returns the local clock offset and the stratum of the server
+func ntpQuery(ctx context.Context, addr string) (time.Duration, uint8, error) {
+	dialer := &net.Dialer{Timeout: ntpTimeout}
+	conn, err := dialer.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ntpTimeout))
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	request := make([]byte, 48)
+	request[0] = 0x23 // leap indicator 0, version 4, mode 3 (client)
+	t1 := time.Now()
+	binary.BigEndian.PutUint64(request[40:], ntpTimestamp(t1))
+	_, err = conn.Write(request)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	t4 := time.Now()
+	if err != nil {
+		return 0, 0, err
+	}
+	if n < 48 || response[0]&0x07 != 4 {
+		return 0, 0, fmt.Errorf("Invalid NTP response from %s", addr)
+	}
+	if binary.BigEndian.Uint64(response[24:]) != binary.BigEndian.Uint64(request[40:]) {
+		return 0, 0, fmt.Errorf("Unexpected NTP response origin timestamp from %s", addr)
+	}
+	t2 := ntpTime(binary.BigEndian.Uint64(response[32:]))
+	t3 := ntpTime(binary.BigEndian.Uint64(response[40:]))
+	offset := (t2.Sub(t1) + t3.Sub(t4)) / 2
+	return offset, response[1], nil
+}
+
+// ntpTimestamp returns the NTP 64 bits timestamp (32 bits seconds and 32 bits fraction) of a time
+func ntpTimestamp(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / 1e9
+	return seconds<<32 | fraction
+}
+
+// ntpTime returns the time of a NTP 64 bits timestamp
+func ntpTime(timestamp uint64) time.Time {
+	seconds := int64(timestamp>>32) - ntpEpochOffset
+	nanoseconds := int64((timestamp & 0xffffffff) * 1e9 >> 32)
+	return time.Unix(seconds, nanoseconds)
+}
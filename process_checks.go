@@ -0,0 +1,65 @@
+package gochecks
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"io/ioutil"
+	"path/filepath"
+)
+
+const procPath = "/proc"
+
+// NewProcessChecker returns a check function that count the running processes whose name or command line matches the
+// processNamePattern regular expression, returning the count as metric. The state is critical when the count is not
+// between minCount and maxCount (both included). Only supported in linux
+func NewProcessChecker(host, service, processNamePattern string, minCount, maxCount int) CheckFunction {
+	return func() Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		pattern, err := regexp.Compile(processNamePattern)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		count, err := countProcesses(procPath, pattern)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		result.Metric = float32(count)
+		result.Description = fmt.Sprintf("%d processes matching %s (allowed %d-%d)", count, processNamePattern, minCount, maxCount)
+		if count >= minCount && count <= maxCount {
+			result.State = StateOK
+		}
+		return result
+	}
+}
+
+// countProcesses returns the number of processes of a proc filesystem whose name or command line matches the pattern
+func countProcesses(proc string, pattern *regexp.Regexp) (int, error) {
+	entries, err := ioutil.ReadDir(proc)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil || !entry.IsDir() {
+			continue
+		}
+		// the process can finish while reading its files
+		name, err := ioutil.ReadFile(filepath.Join(proc, entry.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+		cmdline, _ := ioutil.ReadFile(filepath.Join(proc, entry.Name(), "cmdline"))
+		if pattern.MatchString(strings.TrimSpace(string(name))) ||
+			pattern.Match(bytes.TrimSpace(bytes.Replace(cmdline, []byte{0}, []byte{' '}, -1))) {
+			count++
+		}
+	}
+	return count, nil
+}
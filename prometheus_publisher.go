@@ -0,0 +1,85 @@
+package gochecks
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// prometheusStates value of the gochecks_state gauge for every state
+var prometheusStates = map[string]float64{StateOK: 0, StateWarning: 1, StateCritical: 2, StateUnknown: 3}
+
+// PrometheusPublisher publisher that expose the last result of every check as prometheus gauges, labeled by host,
+// service and tags (sorted and separated by commas): gochecks_metric (the numeric metric), gochecks_state (0 ok,
+// 1 warning, 2 critical, 3 unknown) and gochecks_last_run_timestamp_seconds. It can be used as CheckPublisher and
+// EventPublisher
+type PrometheusPublisher struct {
+	registry *prometheus.Registry
+	metric   *prometheus.GaugeVec
+	state    *prometheus.GaugeVec
+	lastRun  *prometheus.GaugeVec
+}
+
+// NewPrometheusPublisher return a PrometheusPublisher with its own registry
+func NewPrometheusPublisher() *PrometheusPublisher {
+	labels := []string{"host", "service", "tags"}
+	p := &PrometheusPublisher{
+		registry: prometheus.NewRegistry(),
+		metric: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "gochecks_metric",
+			Help: "Metric of the last check result"}, labels),
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "gochecks_state",
+			Help: "State of the last check result (0 ok, 1 warning, 2 critical, 3 unknown)"}, labels),
+		lastRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "gochecks_last_run_timestamp_seconds",
+			Help: "Unix time of the last check result"}, labels),
+	}
+	p.registry.MustRegister(p.metric, p.state, p.lastRun)
+	return p
+}
+
+// Handler return the http handler that expose the metrics
+func (p *PrometheusPublisher) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+// ListenAndServe serve the metrics in the /metrics path of the given address
+func (p *PrometheusPublisher) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", p.Handler())
+	return http.ListenAndServe(addr, mux)
+}
+
+// PublishCheckResult update the gauges of the event check
+func (p *PrometheusPublisher) PublishCheckResult(event Event) {
+	p.Publish(event)
+}
+
+// Publish update the gauges of the event check
+func (p *PrometheusPublisher) Publish(event Event) error {
+	tags := append([]string{}, event.Tags...)
+	sort.Strings(tags)
+	labels := prometheus.Labels{"host": event.Host, "service": event.Service, "tags": strings.Join(tags, ",")}
+
+	if value, ok := metricValue(event.Metric); ok {
+		p.metric.With(labels).Set(value)
+	}
+	state, ok := prometheusStates[event.State]
+	if !ok {
+		state = prometheusStates[StateCritical]
+	}
+	p.state.With(labels).Set(state)
+	p.lastRun.With(labels).Set(float64(time.Now().UnixNano()) / 1e9)
+	return nil
+}
+
+// PublishBatch update the gauges of the events checks
+func (p *PrometheusPublisher) PublishBatch(events []Event) error {
+	for _, event := range events {
+		p.Publish(event)
+	}
+	return nil
+}
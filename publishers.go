@@ -1,6 +1,7 @@
 package gochecks
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -20,6 +21,30 @@ type CheckPublisher interface {
 	PublishCheckResult(Event)
 }
 
+// EventPublisher define a publisher that send the events to a destination reporting the errors. It can be used as a
+// Sink with NewEventPublisherSink
+type EventPublisher interface {
+	Publish(Event) error
+	PublishBatch([]Event) error
+}
+
+// NewEventPublisherAdapter return a CheckPublisher that publish the events with the given EventPublisher, logging the
+// errors. It can be used to publish the results of a CheckEngine with an EventPublisher
+func NewEventPublisherAdapter(p EventPublisher) CheckPublisher {
+	return eventPublisherAdapter{p}
+}
+
+type eventPublisherAdapter struct {
+	publisher EventPublisher
+}
+
+func (a eventPublisherAdapter) PublishCheckResult(event Event) {
+	err := a.publisher.Publish(event)
+	if err != nil {
+		log.Println("[error] publishing check", event, err)
+	}
+}
+
 // LogPublisher object to log each check result
 type LogPublisher struct{}
 
@@ -86,7 +111,37 @@ func (p RiemannPublisher) PublishCheckResult(event Event) {
 		return
 	}
 	defer p.client.Close()
-	riemannEvent := goryman.Event{Description: p.normalizeDescriptionLength(event.Description),
+
+	err = sendRiemannEvent(p.client, event)
+	if err != nil {
+		log.Println("[error] sending check", event)
+		return
+	}
+}
+
+// RiemannEventPublisher EventPublisher that send the events to a riemann server using a RiemannSink
+type RiemannEventPublisher struct {
+	sink *RiemannSink
+}
+
+// NewRiemannEventPublisher return a EventPublisher that send the events to the riemann server at the given address
+func NewRiemannEventPublisher(addr string) *RiemannEventPublisher {
+	return &RiemannEventPublisher{sink: NewRiemannSink(addr)}
+}
+
+// Publish send the event to riemann
+func (p *RiemannEventPublisher) Publish(event Event) error {
+	return p.sink.Send(context.Background(), event)
+}
+
+// PublishBatch send the events to riemann using the same connection. It stops at the first error
+func (p *RiemannEventPublisher) PublishBatch(events []Event) error {
+	return p.sink.SendBatch(context.Background(), events)
+}
+
+// sendRiemannEvent send the event using a connected riemann client
+func sendRiemannEvent(client *goryman.GorymanClient, event Event) error {
+	riemannEvent := goryman.Event{Description: normalizeDescriptionLength(event.Description),
 		Host:       event.Host,
 		Service:    event.Service,
 		State:      event.State,
@@ -95,14 +150,10 @@ func (p RiemannPublisher) PublishCheckResult(event Event) {
 		Attributes: event.Attributes,
 		Ttl:        event.TTL}
 
-	err = p.client.SendEvent(&riemannEvent)
-	if err != nil {
-		log.Println("[error] sending check", event)
-		return
-	}
+	return client.SendEvent(&riemannEvent)
 }
 
-func (p RiemannPublisher) normalizeDescriptionLength(description string) string {
+func normalizeDescriptionLength(description string) string {
 	if len(description) < riemannDescriptionMaxLength {
 		return description
 	}
@@ -0,0 +1,136 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	redisTimeout = 5 * time.Second
+)
+
+func redisDial(ctx context.Context, addr, password string) (redis.Conn, error) {
+	return redis.DialContext(ctx, "tcp", addr, redis.DialPassword(password), redis.DialConnectTimeout(redisTimeout),
+		redis.DialReadTimeout(redisTimeout), redis.DialWriteTimeout(redisTimeout))
+}
+
+// NewRedisChecker returns a check function that connect to a redis server (authenticating when password is not empty)
+// and send a PING. The time to connect and receive the PONG is returned as metric
+func NewRedisChecker(host, service, addr, password string) CheckFunction {
+	return NewRedisCheckerCtx(host, service, addr, password).CheckFunction()
+}
+
+// NewRedisCheckerCtx returns a CheckFunctionCtx like NewRedisChecker that honor the context
+func NewRedisCheckerCtx(host, service, addr, password string) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		var t1 = time.Now()
+		conn, err := redisDial(ctx, addr, password)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
+		}
+		defer conn.Close()
+		pong, err := redis.String(redis.DoContext(conn, ctx, "PING"))
+		milliseconds := float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error(), Metric: milliseconds}
+		}
+		if pong != "PONG" {
+			return Event{Host: host, Service: service, State: StateCritical, Description: "Unexpected response " + pong, Metric: milliseconds}
+		}
+		return Event{Host: host, Service: service, State: StateOK, Metric: milliseconds}
+	}
+}
+
+// NewRedisInfoChecker returns a multi check function that get the INFO of a redis server and return the
+// connected_clients and used_memory (bytes) values as events with the service name followed by the field name. When the
+// INFO can't be obtained a single critical event is returned
+func NewRedisInfoChecker(host, service, addr, password string) MultiCheckFunction {
+	return func() []Event {
+		conn, err := redisDial(context.Background(), addr, password)
+		if err != nil {
+			return []Event{{Host: host, Service: service, State: StateCritical, Description: err.Error()}}
+		}
+		defer conn.Close()
+		info, err := redis.String(conn.Do("INFO"))
+		if err != nil {
+			return []Event{{Host: host, Service: service, State: StateCritical, Description: err.Error()}}
+		}
+		fields := redisInfoFields(info)
+
+		results := []Event{}
+		for _, name := range []string{"connected_clients", "used_memory"} {
+			result := Event{Host: host, Service: service + " " + name, State: StateCritical}
+			value, err := strconv.ParseFloat(fields[name], 64)
+			if err != nil {
+				result.Description = fmt.Sprintf("Invalid %s %s", name, fields[name])
+			} else {
+				result.State = StateOK
+				result.Metric = float32(value)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+}
+
+// redisInfoFields returns the fields of a INFO or CLUSTER INFO response
+func redisInfoFields(info string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(info, "\n") {
+		if i := strings.Index(line, ":"); i >= 0 && !strings.HasPrefix(line, "#") {
+			fields[line[:i]] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return fields
+}
+
+// NewRedisClusterCheck returns a check function that get the CLUSTER INFO of a redis cluster node and return as metric
+// the number of known nodes. The state is "critical" when the cluster_state is not ok and "warning" when the number of
+// known nodes is lower than expectedNodes
+func NewRedisClusterCheck(host, service, addr string, expectedNodes int, timeout time.Duration) CheckFunction {
+	return NewRedisClusterCheckCtx(host, service, addr, expectedNodes, timeout).CheckFunction()
+}
+
+// NewRedisClusterCheckCtx returns a CheckFunctionCtx like NewRedisClusterCheck that honor the context
+func NewRedisClusterCheckCtx(host, service, addr string, expectedNodes int, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		conn, err := redis.DialContext(ctx, "tcp", addr, redis.DialConnectTimeout(timeout), redis.DialReadTimeout(timeout), redis.DialWriteTimeout(timeout))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer conn.Close()
+
+		info, err := redis.String(redis.DoContext(conn, ctx, "CLUSTER", "INFO"))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		fields := redisInfoFields(info)
+
+		nodes, err := strconv.Atoi(fields["cluster_known_nodes"])
+		if err != nil {
+			result.Description = "Invalid cluster_known_nodes " + fields["cluster_known_nodes"]
+			return result
+		}
+		result.Metric = float32(nodes)
+
+		switch {
+		case fields["cluster_state"] != "ok":
+			result.Description = "Cluster state " + fields["cluster_state"]
+		case nodes < expectedNodes:
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("%d known nodes, expected %d", nodes, expectedNodes)
+		default:
+			result.State = StateOK
+		}
+		return result
+	}
+}
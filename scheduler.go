@@ -0,0 +1,105 @@
+package gochecks
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aleasoluciones/goaleasoluciones/scheduledtask"
+)
+
+// Scheduler execute periodically the registered checks, each one with its own interval, while it is started. The
+// results are sent to the results channel (when not nil) and to the sink (when not nil)
+type Scheduler struct {
+	results chan<- Event
+	sink    Sink
+
+	mutex   sync.Mutex
+	checks  []scheduledCheck
+	tasks   []*scheduledtask.ScheduledTask
+	running bool
+}
+
+type scheduledCheck struct {
+	check    MultiCheckFunction
+	interval time.Duration
+}
+
+// NewScheduler return a stopped Scheduler that send the results of the checks to the given channel and sink (both can
+// be nil). Use NewMultiSink to send the results to several sinks and NewEventPublisherSink to use an EventPublisher.
+// The channel is never blocked: it should be buffered and drained, as the results that don't fit are dropped (and
+// logged)
+func NewScheduler(results chan<- Event, sink Sink) *Scheduler {
+	return &Scheduler{results: results, sink: sink}
+}
+
+// Register add a check to be executed with the given interval. When the scheduler is running the check is scheduled
+// immediately
+func (s *Scheduler) Register(check CheckFunction, interval time.Duration) {
+	s.RegisterMulti(func() []Event {
+		return []Event{check()}
+	}, interval)
+}
+
+// RegisterMulti add a multi check to be executed with the given interval. When the scheduler is running the check is
+// scheduled immediately
+func (s *Scheduler) RegisterMulti(check MultiCheckFunction, interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c := scheduledCheck{check: check, interval: interval}
+	s.checks = append(s.checks, c)
+	if s.running {
+		s.tasks = append(s.tasks, s.schedule(c))
+	}
+}
+
+// Start start the periodic execution of the registered checks. Does nothing when the scheduler is already running
+func (s *Scheduler) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.running {
+		return
+	}
+	s.running = true
+	for _, c := range s.checks {
+		s.tasks = append(s.tasks, s.schedule(c))
+	}
+}
+
+// Stop stop the periodic execution of the checks. The scheduler can be started again
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, task := range s.tasks {
+		task.Stop()
+	}
+	s.tasks = nil
+	s.running = false
+}
+
+func (s *Scheduler) schedule(c scheduledCheck) *scheduledtask.ScheduledTask {
+	return scheduledtask.NewScheduledTask(func() {
+		for _, result := range c.check() {
+			if !IsZero(result) {
+				s.publish(result)
+			}
+		}
+	}, c.interval, 0)
+}
+
+func (s *Scheduler) publish(event Event) {
+	if s.results != nil {
+		select {
+		case s.results <- event:
+		default:
+			log.Println("[error] results channel full, dropping check", event)
+		}
+	}
+	if s.sink != nil {
+		err := s.sink.Send(context.Background(), event)
+		if err != nil {
+			log.Println("[error] sending check", event, err)
+		}
+	}
+}
@@ -7,25 +7,25 @@ import (
 )
 
 func NewSentryUnresolvedIssuesChecker(host, service, sentryBaseUrl, projectName string) CheckFunction {
-	return func() Event {
-		response, err := http.Get(sentryBaseUrl + "/api/0/projects/" + projectName + "/issues/?query=is:unresolved&statsPeriod=24h")
+	return newHTTPCheck(func(s httpSettings) Event {
+		response, err := s.get(s.client(0), sentryBaseUrl+"/api/0/projects/"+projectName+"/issues/?query=is:unresolved&statsPeriod=24h")
 		if err != nil {
-			return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 		}
 		defer response.Body.Close()
 		if response.StatusCode != http.StatusOK {
-			return Event{Host: host, Service: service, State: "critical", Description: fmt.Sprintf("Response %d", response.StatusCode)}
+			return Event{Host: host, Service: service, State: StateCritical, Description: fmt.Sprintf("Response %d", response.StatusCode)}
 		}
 		decoder := json.NewDecoder(response.Body)
 		var unresolvedIssues []interface{}
 		err = decoder.Decode(&unresolvedIssues)
 		if err != nil {
-			return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+			return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 		}
-		state := "ok"
+		state := StateOK
 		if len(unresolvedIssues) != 0 {
-			state = "critical"
+			state = StateCritical
 		}
 		return Event{Host: host, Service: service, State: state, Metric: len(unresolvedIssues)}
-	}
+	})
 }
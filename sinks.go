@@ -0,0 +1,218 @@
+package gochecks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/aleasoluciones/goryman"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Sink define a destination where the check results (events) are sent
+type Sink interface {
+	Send(ctx context.Context, e Event) error
+}
+
+// NewCheckEngineWithSink return a CheckEngine that send the results of the periodic checks to the given sink
+func NewCheckEngineWithSink(sink Sink) *CheckEngine {
+	return NewCheckEngine([]CheckPublisher{sinkPublisher{sink}})
+}
+
+// sinkPublisher publisher that send the events to a sink
+type sinkPublisher struct {
+	sink Sink
+}
+
+func (p sinkPublisher) PublishCheckResult(event Event) {
+	err := p.sink.Send(context.Background(), event)
+	if err != nil {
+		log.Println("[error] sending check", event, err)
+	}
+}
+
+// NewEventPublisherSink return a sink that publish the events with the given EventPublisher, to use it for example
+// with the Scheduler
+func NewEventPublisherSink(p EventPublisher) Sink {
+	return eventPublisherSink{p}
+}
+
+type eventPublisherSink struct {
+	publisher EventPublisher
+}
+
+func (s eventPublisherSink) Send(ctx context.Context, e Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.publisher.Publish(e)
+}
+
+// RiemannSink sink that send the events to a riemann server
+type RiemannSink struct {
+	mutex  sync.Mutex
+	client *goryman.GorymanClient
+}
+
+// NewRiemannSink return a sink that send the events to the riemann server at the given address
+func NewRiemannSink(addr string) *RiemannSink {
+	return &RiemannSink{client: goryman.NewGorymanClient(addr)}
+}
+
+// Send send the event to riemann
+func (s *RiemannSink) Send(ctx context.Context, e Event) error {
+	return s.SendBatch(ctx, []Event{e})
+}
+
+// SendBatch send the events to riemann using the same connection. It stops at the first error
+func (s *RiemannSink) SendBatch(ctx context.Context, events []Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	err := s.client.Connect()
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+	for _, e := range events {
+		err = sendRiemannEvent(s.client, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// influxDBTimeout timeout of the InfluxDB write requests
+const influxDBTimeout = 10 * time.Second
+
+// InfluxDBSink sink that write the events to a InfluxDB database using the line protocol. Each event is written as a
+// point of the "gochecks" measurement, with the host, service and state as tags and the metric as value field (omitted
+// when the metric is not numeric)
+type InfluxDBSink struct {
+	writeURL string
+	client   *http.Client
+}
+
+// NewInfluxDBSink return a sink that write the events to the given database of a InfluxDB server
+// (for example http://localhost:8086)
+func NewInfluxDBSink(influxURL, database string) *InfluxDBSink {
+	return &InfluxDBSink{
+		writeURL: strings.TrimRight(influxURL, "/") + "/write?db=" + url.QueryEscape(database),
+		client:   &http.Client{Timeout: influxDBTimeout},
+	}
+}
+
+// Send write the event to InfluxDB
+func (s *InfluxDBSink) Send(ctx context.Context, e Event) error {
+	escapeTag := strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	escapeString := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	fields := fmt.Sprintf(`description="%s"`, escapeString.Replace(e.Description))
+	if value, ok := metricValue(e.Metric); ok {
+		fields = fmt.Sprintf("value=%v,%s", value, fields)
+	}
+	line := fmt.Sprintf(`gochecks,host=%s,service=%s,state=%s %s`,
+		escapeTag.Replace(e.Host), escapeTag.Replace(e.Service), escapeTag.Replace(e.State), fields)
+
+	request, err := http.NewRequest("POST", s.writeURL, strings.NewReader(line))
+	if err != nil {
+		return err
+	}
+	response, err := s.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("InfluxDB response %d", response.StatusCode)
+	}
+	return nil
+}
+
+// PrometheusGaugeSink sink that set the metric of the events in a prometheus gauge, so it is exported on each scrape
+type PrometheusGaugeSink struct {
+	gauge *prometheus.GaugeVec
+}
+
+// NewPrometheusGaugeSink return a sink that set the given gauge, that should have the labels "host" and "service"
+func NewPrometheusGaugeSink(gauge *prometheus.GaugeVec) *PrometheusGaugeSink {
+	return &PrometheusGaugeSink{gauge: gauge}
+}
+
+// Send set the gauge for the event host and service with the event metric
+func (s *PrometheusGaugeSink) Send(ctx context.Context, e Event) error {
+	value, ok := metricValue(e.Metric)
+	if !ok {
+		return fmt.Errorf("Not numeric metric %v", e.Metric)
+	}
+	gauge, err := s.gauge.GetMetricWithLabelValues(e.Host, e.Service)
+	if err != nil {
+		return err
+	}
+	gauge.Set(value)
+	return nil
+}
+
+// JSONFileSink sink that append the events to a file as JSON lines
+type JSONFileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// NewJSONFileSink return a sink that append the events to the given file (created if it does not exist)
+func NewJSONFileSink(path string) (*JSONFileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONFileSink{file: file}, nil
+}
+
+// Send append the event to the file
+func (s *JSONFileSink) Send(ctx context.Context, e Event) error {
+	serialized, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.file.Write(append(serialized, '\n'))
+	return err
+}
+
+// Close close the file
+func (s *JSONFileSink) Close() error {
+	return s.file.Close()
+}
+
+// MultiSink sink that send the events to several sinks
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink return a sink that send the events to all the given sinks
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Send send the event to all the sinks, returning the errors of all the failed ones joined with errors.Join
+func (s *MultiSink) Send(ctx context.Context, e Event) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Send(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
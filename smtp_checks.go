@@ -0,0 +1,188 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"crypto/tls"
+	"net/smtp"
+)
+
+// NewSMTPSCheck returns a check function that connect to a SMTPS server (implicit TLS, usually port 465) and send
+// a EHLO. The metric is the time of the TLS handshake plus the EHLO round trip in milliseconds
+func NewSMTPSCheck(host, service, addr string, tlsConfig *tls.Config, timeout time.Duration) CheckFunction {
+	return NewSMTPSCheckCtx(host, service, addr, tlsConfig, timeout).CheckFunction()
+}
+
+// NewSMTPSCheckCtx returns a CheckFunctionCtx like NewSMTPSCheck that honor the context
+func NewSMTPSCheckCtx(host, service, addr string, tlsConfig *tls.Config, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		serverName, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		config := &tls.Config{}
+		if tlsConfig != nil {
+			config = tlsConfig.Clone()
+		}
+		if config.ServerName == "" {
+			config.ServerName = serverName
+		}
+
+		dialer := &net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		defer conn.Close()
+		defer context.AfterFunc(ctx, func() { conn.Close() })()
+		conn.SetDeadline(time.Now().Add(timeout))
+
+		var t1 = time.Now()
+		tlsConn := tls.Client(conn, config)
+		err = tlsConn.Handshake()
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		client, err := smtp.NewClient(tlsConn, serverName)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		defer client.Close()
+		err = client.Hello("localhost")
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		client.Quit()
+		result.State = StateOK
+		return result
+	}
+}
+
+// smtpTimeout timeout of the SMTP checks connections
+const smtpTimeout = 10 * time.Second
+
+// NewSMTPChecker returns a check function that connect to a SMTP server, send a EHLO and, if starttls is true, upgrade
+// the connection with STARTTLS. The metric is the handshake time in milliseconds
+func NewSMTPChecker(host, service, addr string, starttls bool) CheckFunction {
+	return NewSMTPCheckerCtx(host, service, addr, starttls).CheckFunction()
+}
+
+// NewSMTPCheckerCtx returns a CheckFunctionCtx like NewSMTPChecker that honor the context
+func NewSMTPCheckerCtx(host, service, addr string, starttls bool) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		client, err := dialSMTP(ctx, addr, starttls)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		defer client.Close()
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		client.Quit()
+		result.State = StateOK
+		return result
+	}
+}
+
+// NewSMTPDeliveryChecker returns a check function that perform the NewSMTPChecker handshake and send a probe message
+// from the from address to the to address, validating that the server accepts it for delivery. The metric is the
+// time in milliseconds of the whole session
+func NewSMTPDeliveryChecker(host, service, addr string, starttls bool, from, to string) CheckFunction {
+	return NewSMTPDeliveryCheckerCtx(host, service, addr, starttls, from, to).CheckFunction()
+}
+
+// NewSMTPDeliveryCheckerCtx returns a CheckFunctionCtx like NewSMTPDeliveryChecker that honor the context
+func NewSMTPDeliveryCheckerCtx(host, service, addr string, starttls bool, from, to string) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		client, err := dialSMTP(ctx, addr, starttls)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		defer client.Close()
+		defer context.AfterFunc(ctx, func() { client.Close() })()
+		err = sendSMTPProbe(client, from, to, service)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = ctxError(ctx, err).Error()
+			return result
+		}
+		client.Quit()
+		result.State = StateOK
+		return result
+	}
+}
+
+// dialSMTP connect to a SMTP server, send a EHLO and, if starttls is true, upgrade the connection with STARTTLS. The
+// connection is closed if the context is done before the handshake finishes
+func dialSMTP(ctx context.Context, addr string, starttls bool) (*smtp.Client, error) {
+	serverName, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	client, err := smtp.NewClient(conn, serverName)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	err = client.Hello("localhost")
+	if err == nil && starttls {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			err = fmt.Errorf("STARTTLS not supported by %s", addr)
+		} else {
+			err = client.StartTLS(&tls.Config{ServerName: serverName})
+		}
+	}
+	if err != nil {
+		client.Close()
+		return nil, ctxError(ctx, err)
+	}
+	return client, nil
+}
+
+// sendSMTPProbe send a probe message using a connected SMTP client
+func sendSMTPProbe(client *smtp.Client, from, to, service string) error {
+	err := client.Mail(from)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "From: %s\r\nTo: %s\r\nSubject: gochecks %s probe\r\nDate: %s\r\n\r\ngochecks probe message\r\n",
+		from, to, service, time.Now().Format(time.RFC1123Z))
+	if err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -28,9 +28,9 @@ func NewSnmpChecker(host, service, ip, community string, conf SnmpCheckerConf) C
 
 		_, err := snmpGet(ip, community, []string{conf.oidToCheck}, conf.timeout, conf.retries)
 		if err == nil {
-			return Event{Host: host, Service: service, State: "ok", Description: err.Error()}
+			return Event{Host: host, Service: service, State: StateOK, Description: err.Error()}
 		}
-		return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+		return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 	}
 }
 
@@ -47,13 +47,13 @@ func NewC4CMTSTempChecker(host, service, ip, community string, maxAllowedTemp in
 					max = r.Value.(int)
 				}
 			}
-			var state = "critical"
+			var state = StateCritical
 			if max < maxAllowedTemp {
-				state = "ok"
+				state = StateOK
 			}
 			return Event{Host: host, Service: service, State: state, Metric: float32(max)}
 		}
-		return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+		return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 	}
 }
 
@@ -76,13 +76,13 @@ func NewJuniperTempChecker(host, service, ip, community string, maxAllowedTemp u
 	return func() Event {
 		max, err := getMaxValueFromSnmpWalk("1.3.6.1.4.1.2636.3.1.13.1.7", ip, community)
 		if err == nil {
-			var state = "critical"
+			var state = StateCritical
 			if max < maxAllowedTemp {
-				state = "ok"
+				state = StateOK
 			}
 			return Event{Host: host, Service: service, State: state, Metric: float32(max)}
 		}
-		return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+		return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 	}
 }
 
@@ -91,12 +91,12 @@ func NewJuniperCPUChecker(host, service, ip, community string, maxAllowedCPUPerc
 	return func() Event {
 		max, err := getMaxValueFromSnmpWalk("1.3.6.1.4.1.2636.3.1.13.1.8", ip, community)
 		if err == nil {
-			var state = "critical"
+			var state = StateCritical
 			if max < maxAllowedCPUPercent {
-				state = "ok"
+				state = StateOK
 			}
 			return Event{Host: host, Service: service, State: state, Metric: float32(max)}
 		}
-		return Event{Host: host, Service: service, State: "critical", Description: err.Error()}
+		return Event{Host: host, Service: service, State: StateCritical, Description: err.Error()}
 	}
 }
@@ -0,0 +1,62 @@
+package gochecks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// NewSOAPCheck returns a check function that POST the given SOAP envelope to a given url and validate that the
+// expectedXPath expression finds a non empty value in the response. The state is "critical" when the request fails, the
+// response is a SOAP fault or the XPath expression returns nothing
+func NewSOAPCheck(host, service, url, soapAction string, requestEnvelope string, expectedXPath string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		request, err := s.newRequest("POST", url, strings.NewReader(requestEnvelope))
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		request.Header.Set("Content-Type", "text/xml; charset=utf-8")
+		request.Header.Set("SOAPAction", fmt.Sprintf("%q", soapAction))
+
+		var t1 = time.Now()
+		response, err := s.client(timeout).Do(request)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+
+		document, err := xmlquery.Parse(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = fmt.Sprintf("Response %d, invalid XML: %s", response.StatusCode, err)
+			return result
+		}
+		if fault := xmlquery.FindOne(document, "//*[local-name()='Fault']"); fault != nil {
+			result.Description = fmt.Sprintf("SOAP fault: %s", strings.TrimSpace(fault.InnerText()))
+			return result
+		}
+		if response.StatusCode != 200 {
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		node, err := xmlquery.Query(document, expectedXPath)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		if node == nil || strings.TrimSpace(node.InnerText()) == "" {
+			result.Description = fmt.Sprintf("No value for %s", expectedXPath)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}
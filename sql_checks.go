@@ -0,0 +1,20 @@
+package gochecks
+
+import (
+	"database/sql"
+)
+
+// NewDatabasePoolCheck returns a multi check function that return the connection pool stats of a database handle as
+// events with the service name followed by open_connections, in_use, idle, wait_count and wait_duration (milliseconds)
+func NewDatabasePoolCheck(host, service string, db *sql.DB) MultiCheckFunction {
+	return func() []Event {
+		stats := db.Stats()
+		return []Event{
+			{Host: host, Service: service + " open_connections", State: StateOK, Metric: float32(stats.OpenConnections)},
+			{Host: host, Service: service + " in_use", State: StateOK, Metric: float32(stats.InUse)},
+			{Host: host, Service: service + " idle", State: StateOK, Metric: float32(stats.Idle)},
+			{Host: host, Service: service + " wait_count", State: StateOK, Metric: float32(stats.WaitCount)},
+			{Host: host, Service: service + " wait_duration", State: StateOK, Metric: float32(stats.WaitDuration.Nanoseconds() / 1e6)},
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package gochecks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// NewSSHChecker returns a check function that connect to a ssh server and read its banner (as SSH-2.0-OpenSSH_8.9). The
+// time to connect and receive the banner is returned as metric and the banner as description
+func NewSSHChecker(host, service, addr string, timeout time.Duration) CheckFunction {
+	return NewSSHCheckerCtx(host, service, addr, timeout).CheckFunction()
+}
+
+// NewSSHCheckerCtx returns a CheckFunctionCtx like NewSSHChecker that honor the context
+func NewSSHCheckerCtx(host, service, addr string, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		dialer := &net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer conn.Close()
+		conn.SetDeadline(t1.Add(timeout))
+		defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+		// the server can send other lines before the banner
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			if err != nil {
+				result.Description = ctxError(ctx, err).Error()
+				return result
+			}
+			if strings.HasPrefix(line, "SSH-") {
+				result.State = StateOK
+				result.Description = strings.TrimSpace(line)
+				return result
+			}
+		}
+	}
+}
+
+// NewSSHCheckerWithFingerprint returns a check function like NewSSHChecker that also validate that the SHA256
+// fingerprint of the server host key is the expected one (as SHA256:...). The state is "critical" when it doesn't match
+func NewSSHCheckerWithFingerprint(host, service, addr, fingerprint string, timeout time.Duration) CheckFunction {
+	return NewSSHCheckerWithFingerprintCtx(host, service, addr, fingerprint, timeout).CheckFunction()
+}
+
+// NewSSHCheckerWithFingerprintCtx returns a CheckFunctionCtx like NewSSHCheckerWithFingerprint that honor the context
+func NewSSHCheckerWithFingerprintCtx(host, service, addr, fingerprint string, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var serverFingerprint string
+		config := &ssh.ClientConfig{
+			User: "gochecks",
+			HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+				serverFingerprint = ssh.FingerprintSHA256(key)
+				if serverFingerprint != fingerprint {
+					return fmt.Errorf("Host key fingerprint %s, expected %s", serverFingerprint, fingerprint)
+				}
+				return nil
+			},
+			Timeout: timeout,
+		}
+
+		var t1 = time.Now()
+		err := sshHandshake(ctx, addr, config)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		// without credentials the authentication fails after the host key is validated
+		if serverFingerprint != fingerprint {
+			if err != nil {
+				result.Description = ctxError(ctx, err).Error()
+			}
+			return result
+		}
+		result.State = StateOK
+		result.Description = serverFingerprint
+		return result
+	}
+}
+
+// sshHandshake connect to a ssh server like ssh.Dial, closing the connection when the context is done
+func sshHandshake(ctx context.Context, addr string, config *ssh.ClientConfig) error {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if config.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(config.Timeout))
+	}
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		return err
+	}
+	ssh.NewClient(c, chans, reqs).Close()
+	return nil
+}
@@ -0,0 +1,109 @@
+package gochecks
+
+import (
+	"sync"
+	"time"
+)
+
+// StateTransitionStore define a store for the state changes of a check
+type StateTransitionStore interface {
+	Record(from, to string, at time.Time)
+}
+
+// RecordStateTransitions returns a new check function that record in the given store every change of the state of the
+// results generated by the initial check function. The first result is recorded as a transition from an empty state
+func (f CheckFunction) RecordStateTransitions(store StateTransitionStore) CheckFunction {
+	var mutex sync.Mutex
+	var lastState string
+	var initialized bool
+	return func() Event {
+		result := f()
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !initialized || result.State != lastState {
+			store.Record(lastState, result.State, time.Now())
+			lastState = result.State
+			initialized = true
+		}
+		return result
+	}
+}
+
+type stateTransition struct {
+	from string
+	to   string
+	at   time.Time
+}
+
+// InMemoryStateTransitionStore state transition store that keep the transitions in memory and can calculate the uptime
+type InMemoryStateTransitionStore struct {
+	mutex       sync.Mutex
+	maxWindow   time.Duration
+	transitions []stateTransition
+}
+
+// NewInMemoryStateTransitionStore return a new empty InMemoryStateTransitionStore that keep all the transitions
+func NewInMemoryStateTransitionStore() *InMemoryStateTransitionStore {
+	return &InMemoryStateTransitionStore{}
+}
+
+// NewInMemoryStateTransitionStoreWithMaxWindow return a new empty InMemoryStateTransitionStore that keep only the
+// transitions needed to calculate the uptime of windows up to maxWindow (the largest window used), discarding the
+// older ones. The larger windows are clamped to maxWindow
+func NewInMemoryStateTransitionStoreWithMaxWindow(maxWindow time.Duration) *InMemoryStateTransitionStore {
+	return &InMemoryStateTransitionStore{maxWindow: maxWindow}
+}
+
+// Record store a state transition, discarding the transitions that ended before the largest window
+func (s *InMemoryStateTransitionStore) Record(from, to string, at time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.transitions = append(s.transitions, stateTransition{from: from, to: to, at: at})
+	if s.maxWindow <= 0 {
+		return
+	}
+	// the last transition before the window start is kept, as it is the state at the start of the window
+	start := at.Add(-s.maxWindow)
+	expired := 0
+	for expired+1 < len(s.transitions) && s.transitions[expired+1].at.Before(start) {
+		expired++
+	}
+	// the slice is not copied, the expired transitions are released when append reallocates it
+	s.transitions = s.transitions[expired:]
+}
+
+// Uptime return the fraction (0 to 1) of the time in the given window (until now) that the check was in "ok" state.
+// The time before the first recorded transition is not taken into account and a window larger than the max window of
+// the store is clamped to it. Returns 0 when there is nothing recorded
+func (s *InMemoryStateTransitionStore) Uptime(window time.Duration) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.maxWindow > 0 && window > s.maxWindow {
+		window = s.maxWindow
+	}
+
+	now := time.Now()
+	start := now.Add(-window)
+	var observed, up time.Duration
+	for i, transition := range s.transitions {
+		from := transition.at
+		to := now
+		if i+1 < len(s.transitions) {
+			to = s.transitions[i+1].at
+		}
+		if to.Before(start) {
+			continue
+		}
+		if from.Before(start) {
+			from = start
+		}
+		observed += to.Sub(from)
+		if transition.to == StateOK {
+			up += to.Sub(from)
+		}
+	}
+	if observed == 0 {
+		return 0
+	}
+	return float64(up) / float64(observed)
+}
@@ -0,0 +1,76 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package gochecks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"log/syslog"
+)
+
+// NewSyslogCheck returns a check function that connect to a syslog server (network "udp" or "tcp") and write a test
+// message with the given priority, in the log/syslog format. With tcp the connection is half closed after the message
+// and the server must close it too, confirming that the message was read. With udp the write success does not
+// guarantee the delivery
+func NewSyslogCheck(host, service, addr, network string, priority syslog.Priority, timeout time.Duration) CheckFunction {
+	return func() Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		err := sendSyslogMessage(network, addr, syslogMessage(priority, fmt.Sprintf("gochecks test message from %s %s", host, service)), timeout)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			result.Description = "syslog check timed out"
+			return result
+		}
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+
+		result.State = StateOK
+		if network == "udp" || network == "udp4" || network == "udp6" {
+			result.Description = "Message sent using udp, delivery not guaranteed"
+		}
+		return result
+	}
+}
+
+// syslogMessage returns a message formatted as log/syslog does for the remote servers
+func syslogMessage(priority syslog.Priority, message string) string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("<%d>%s %s gochecks[%d]: %s\n", priority, time.Now().Format(time.RFC3339), hostname, os.Getpid(), message)
+}
+
+// sendSyslogMessage write the message to a syslog server in the given timeout. With tcp the connection is half closed
+// and the server closing the connection is waited, so the message is known to be flushed and read
+func sendSyslogMessage(network, addr, message string, timeout time.Duration) error {
+	conn, err := net.DialTimeout(network, addr, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	_, err = io.WriteString(conn, message)
+	if err != nil {
+		return err
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+	err = tcpConn.CloseWrite()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(io.Discard, tcpConn)
+	return err
+}
@@ -0,0 +1,90 @@
+//go:build integration && !windows && !nacl && !plan9
+// +build integration,!windows,!nacl,!plan9
+
+package gochecks_test
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"log/syslog"
+
+	. "github.com/aleasoluciones/gochecks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogCheckTCP(t *testing.T) {
+	t.Parallel()
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		message, _ := io.ReadAll(conn)
+		conn.Close()
+		received <- string(message)
+	}()
+
+	checkResult := NewSyslogCheck("host", "service", listener.Addr().String(), "tcp", syslog.LOG_INFO|syslog.LOG_DAEMON, time.Second)()
+
+	assert.Equal(t, "ok", checkResult.State)
+	message := <-received
+	assert.True(t, strings.HasPrefix(message, "<30>"), message)
+	assert.Contains(t, message, "gochecks test message from host service")
+}
+
+func TestSyslogCheckUDP(t *testing.T) {
+	t.Parallel()
+
+	conn, _ := net.ListenPacket("udp", "127.0.0.1:0")
+	defer conn.Close()
+
+	checkResult := NewSyslogCheck("host", "service", conn.LocalAddr().String(), "udp", syslog.LOG_INFO, time.Second)()
+
+	assert.Equal(t, "ok", checkResult.State)
+	assert.Equal(t, "Message sent using udp, delivery not guaranteed", checkResult.Description)
+	buffer := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, _ := conn.ReadFrom(buffer)
+	assert.Contains(t, string(buffer[:n]), "gochecks test message from host service")
+}
+
+func TestSyslogCheckTimeout(t *testing.T) {
+	t.Parallel()
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		time.Sleep(time.Second)
+	}()
+
+	checkResult := NewSyslogCheck("host", "service", listener.Addr().String(), "tcp", syslog.LOG_INFO, 100*time.Millisecond)()
+
+	assert.Equal(t, "critical", checkResult.State)
+	assert.Equal(t, "syslog check timed out", checkResult.Description)
+}
+
+func TestSyslogCheckConnectionRefused(t *testing.T) {
+	t.Parallel()
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := listener.Addr().String()
+	listener.Close()
+
+	checkResult := NewSyslogCheck("host", "service", addr, "tcp", syslog.LOG_INFO, time.Second)()
+
+	assert.Equal(t, "critical", checkResult.State)
+}
@@ -0,0 +1,129 @@
+package gochecks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// NewHTTPCertChainCheck returns a check function that connect via TLS to a given address (host:port) and validate that
+// the server sends a complete certificate chain (leaf and intermediates) trusted by the system roots. The state is
+// "warning" for a self signed certificate or a chain without intermediates and "critical" when the chain is not trusted.
+// The handshake time is returned as metric
+func NewHTTPCertChainCheck(host, service, addr string, timeout time.Duration) CheckFunction {
+	return NewHTTPCertChainCheckCtx(host, service, addr, timeout).CheckFunction()
+}
+
+// NewHTTPCertChainCheckCtx returns a CheckFunctionCtx like NewHTTPCertChainCheck that honor the context
+func NewHTTPCertChainCheckCtx(host, service, addr string, timeout time.Duration) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		serverName, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		// the chain is verified below, to distinguish the missing intermediates from the untrusted roots
+		dialer := &tls.Dialer{
+			NetDialer: &net.Dialer{Timeout: timeout},
+			Config:    &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+		}
+		var t1 = time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer conn.Close()
+
+		certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+		if len(certificates) == 0 {
+			result.Description = "No certificates"
+			return result
+		}
+		leaf := certificates[0]
+		// CheckSignatureFrom is not used as it requires the CA flag, that many self signed leaf certificates don't have
+		if len(certificates) == 1 && leaf.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature) == nil {
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("Self signed certificate %s", leaf.Subject)
+			return result
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, certificate := range certificates[1:] {
+			intermediates.AddCert(certificate)
+		}
+		chains, err := leaf.Verify(x509.VerifyOptions{DNSName: serverName, Intermediates: intermediates})
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+
+		if len(certificates) < 2 || len(chains[0]) < 2 {
+			result.State = StateWarning
+			result.Description = fmt.Sprintf("Chain without intermediate certificates (%d certificates sent)", len(certificates))
+			return result
+		}
+		result.State = StateOK
+		return result
+	}
+}
+
+const (
+	tlsCertificateTimeout = 10 * time.Second
+)
+
+// NewTLSCertificateChecker returns a check function that connect via TLS to a given address (host:port) and return as
+// metric the days until the server certificate expires. The state is "critical" when it expires in critDays days or
+// less and "warning" when it expires in warnDays days or less. The certificate is not verified, so the expired
+// certificates are reported too
+func NewTLSCertificateChecker(host, service, addr string, warnDays, critDays int) CheckFunction {
+	return NewTLSCertificateCheckerCtx(host, service, addr, warnDays, critDays).CheckFunction()
+}
+
+// NewTLSCertificateCheckerCtx returns a CheckFunctionCtx like NewTLSCertificateChecker that honor the context
+func NewTLSCertificateCheckerCtx(host, service, addr string, warnDays, critDays int) CheckFunctionCtx {
+	return func(ctx context.Context) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		serverName, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		dialer := &tls.Dialer{
+			NetDialer: &net.Dialer{Timeout: tlsCertificateTimeout},
+			Config:    &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		defer conn.Close()
+
+		certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+		if len(certificates) == 0 {
+			result.Description = "No certificates"
+			return result
+		}
+		leaf := certificates[0]
+		days := float32(leaf.NotAfter.Sub(time.Now()).Hours() / 24)
+		result.Metric = days
+		result.Description = fmt.Sprintf("%s expires %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.RFC3339))
+		switch {
+		case days <= float32(critDays):
+		case days <= float32(warnDays):
+			result.State = StateWarning
+		default:
+			result.State = StateOK
+		}
+		return result
+	}
+}
@@ -0,0 +1,54 @@
+package gochecks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// NewHTTPXMLCheck returns a check function that get a given url and validate that the text of the node selected by the
+// XPath expression in the XML response is the expected value. The state is "critical" when the request fails, the
+// response is not valid XML, there is no node or its value is not the expected one
+func NewHTTPXMLCheck(host, service, url, xpathExpression, expectedValue string, timeout time.Duration) CheckFunction {
+	return newHTTPCheck(func(s httpSettings) Event {
+		result := Event{Host: host, Service: service, State: StateCritical}
+
+		var t1 = time.Now()
+		response, err := s.get(s.client(timeout), url)
+		if err != nil {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = err.Error()
+			return result
+		}
+		defer response.Body.Close()
+		if response.StatusCode != 200 {
+			result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+			result.Description = fmt.Sprintf("Response %d", response.StatusCode)
+			return result
+		}
+
+		document, err := xmlquery.Parse(response.Body)
+		result.Metric = float32((time.Now().Sub(t1)).Nanoseconds() / 1e6)
+		if err != nil {
+			result.Description = fmt.Sprintf("Invalid XML: %s", err)
+			return result
+		}
+		node, err := xmlquery.Query(document, xpathExpression)
+		if err != nil {
+			result.Description = err.Error()
+			return result
+		}
+		if node == nil {
+			result.Description = fmt.Sprintf("No node for %s", xpathExpression)
+			return result
+		}
+		if value := strings.TrimSpace(node.InnerText()); value != expectedValue {
+			result.Description = fmt.Sprintf("%s is %s, expected %s", xpathExpression, value, expectedValue)
+			return result
+		}
+		result.State = StateOK
+		return result
+	})
+}